@@ -8,6 +8,9 @@ import (
 	"github.com/minimal1/twf-clone/internal/terminal"
 )
 
+// 데모에서는 숨김 파일까지 모두 표시
+var viewOptions = filetree.ViewOptions{ShowHidden: true}
+
 type FileBrowserApp struct {
 	wd string
 
@@ -96,7 +99,7 @@ func (app *FileBrowserApp) drawHeader(row, col int) {
 }
 
 func (app *FileBrowserApp) drawTree(row, col int) {
-	visibleNodes := app.walker.GetVisibleNodes()
+	visibleNodes := app.walker.GetVisibleNodes(viewOptions)
 
 	for i, node := range visibleNodes {
 		color := terminal.ColorWhite
@@ -141,13 +144,13 @@ func (app *FileBrowserApp) HandleEvent(event terminal.Event) {
 }
 
 func (app *FileBrowserApp) handleMoveUp() {
-	if node := app.walker.GetPrevVisibleNode(app.currentNode); node != nil {
+	if node := app.walker.GetPrevVisibleNode(app.currentNode, viewOptions); node != nil {
 		app.currentNode = node
 	}
 }
 
 func (app *FileBrowserApp) handleMoveDown() {
-	if node := app.walker.GetNextVisibleNode(app.currentNode); node != nil {
+	if node := app.walker.GetNextVisibleNode(app.currentNode, viewOptions); node != nil {
 		app.currentNode = node
 	}
 }
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/minimal1/twf-clone/internal/filetree"
+	"github.com/minimal1/twf-clone/internal/preview"
+	"github.com/minimal1/twf-clone/internal/state"
+	"github.com/minimal1/twf-clone/internal/terminal"
+)
+
+// 비동기로 실행한 셸 명령의 결과
+type commandResult struct {
+	command string
+	lines   []string
+	err     error
+}
+
+// ! 는 터미널에서 실행하고 키를 누를 때까지 출력을 보여 주고,
+// : 는 백그라운드에서 실행해 출력을 output 뷰에 모음
+func (app *App) startCommand(wait bool) {
+	app.commandWait = wait
+
+	viewState := app.appState.View()
+	viewState.SetInputMode(state.InputModeCommand)
+	viewState.SetInputText("")
+	app.updateCommandPrompt()
+}
+
+func (app *App) updateCommandPrompt() {
+	prefix := " :"
+	if app.commandWait {
+		prefix = " !"
+	}
+	app.appState.View().SetPrompt(prefix + app.appState.View().GetInputText() + "_")
+}
+
+func (app *App) submitCommand() {
+	text := strings.TrimSpace(app.appState.View().GetInputText())
+	app.endInput()
+	if text == "" {
+		return
+	}
+
+	current := app.appState.Cursor().GetCurrentNode()
+	if current == nil {
+		return
+	}
+
+	targets := app.appState.Selection().GetSelectedNodes()
+	if len(targets) == 0 {
+		targets = []*filetree.TreeNode{current}
+	}
+
+	command := expandCommand(text, current, targets)
+	dir := commandDir(current)
+
+	if app.commandWait {
+		app.runCommandInTerminal(command, dir)
+	} else {
+		app.runCommandAsync(text, command, dir)
+	}
+}
+
+// {} 는 커서 경로, {+} 는 선택된 경로 모두 (없으면 커서 경로), {dir} 은 커서 위치의 디렉토리,
+// {name} 은 커서 노드의 이름으로 치환. 모두 따옴표로 감쌈.
+// 명령은 commandDir 에서 실행하므로 경로는 절대 경로로 바꿈
+func expandCommand(command string, current *filetree.TreeNode, targets []*filetree.TreeNode) string {
+	quoted := make([]string, len(targets))
+	for i, node := range targets {
+		quoted[i] = preview.ShellQuote(absPath(node.Path))
+	}
+
+	return strings.NewReplacer(
+		"{+}", strings.Join(quoted, " "),
+		"{dir}", preview.ShellQuote(absPath(commandDir(current))),
+		"{name}", preview.ShellQuote(current.Name),
+		"{}", preview.ShellQuote(absPath(current.Path)),
+	).Replace(command)
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// 명령을 실행할 디렉토리. 커서가 파일이면 그 파일이 있는 디렉토리
+func commandDir(node *filetree.TreeNode) string {
+	if node.IsDir {
+		return node.Path
+	}
+	return filepath.Dir(node.Path)
+}
+
+func (app *App) runCommandInTerminal(command, dir string) {
+	err := app.releaseTerminal(func(ctx context.Context) error {
+		tty, err := terminal.OpenTTY()
+		if err != nil {
+			return err
+		}
+		defer tty.Close()
+
+		cmd := terminalCommand(ctx, "sh", "-c", command)
+		cmd.Dir = dir
+		cmd.Stdin = tty
+		cmd.Stdout = tty
+		cmd.Stderr = tty
+		runErr := cmd.Run()
+
+		status := "done"
+		if runErr != nil {
+			status = runErr.Error()
+		}
+		fmt.Fprintf(tty, "\n[%s] Press any key to continue", status)
+
+		// 키 하나만 받도록 잠시 raw 모드로
+		app.term.EnableRawMode()
+		app.term.Read(make([]byte, 16))
+		app.term.DisableRawMode()
+		return runErr
+	})
+	if err != nil {
+		app.appState.View().SetMessage(fmt.Sprintf("!%s: %v", command, err))
+	}
+
+	app.refreshAll()
+}
+
+func (app *App) runCommandAsync(text, command, dir string) {
+	app.appState.View().SetMessage(fmt.Sprintf("Running %s…", text))
+
+	go func() {
+		cmd := exec.CommandContext(app.commandCtx, "sh", "-c", command)
+		cmd.Dir = dir
+		output, err := cmd.CombinedOutput()
+
+		var lines []string
+		for _, line := range strings.Split(strings.TrimRight(string(output), "\n"), "\n") {
+			lines = append(lines, preview.Sanitize(line))
+		}
+		if len(output) == 0 {
+			lines = nil
+		}
+
+		select {
+		case app.commandResults <- commandResult{command: text, lines: lines, err: err}:
+		case <-app.commandCtx.Done():
+		}
+	}()
+}
+
+func (app *App) handleCommandResult(result commandResult) {
+	title := result.command + " (done)"
+	if result.err != nil {
+		title = fmt.Sprintf("%s (%v)", result.command, result.err)
+	}
+
+	viewState := app.appState.View()
+	viewState.SetOutput(title, result.lines)
+	viewState.SetMessage(title)
+
+	// 다른 화면을 보고 있거나 입력 중이면 방해하지 않음
+	if viewState.GetMode() == state.ViewModeNormal && !viewState.IsWaitingForInput() {
+		viewState.SetMode(state.ViewModeOutput)
+	}
+
+	app.refreshAll()
+}
+
+func (app *App) toggleOutputView() {
+	viewState := app.appState.View()
+	if viewState.GetMode() == state.ViewModeOutput {
+		viewState.SetMode(state.ViewModeNormal)
+		return
+	}
+	viewState.SetMode(state.ViewModeOutput)
+}
+
+// output 뷰에서의 키 처리
+func (app *App) handleOutputKey(event terminal.KeyPressEvent) {
+	viewState := app.appState.View()
+	page := max(app.height-2, 1)
+
+	switch {
+	case event.Rune == 'j' || event.Key == terminal.KeyArrowDown:
+		viewState.ScrollOutput(1)
+	case event.Rune == 'k' || event.Key == terminal.KeyArrowUp:
+		viewState.ScrollOutput(-1)
+	case event.Rune == ' ' || event.Key == terminal.KeyCtrlD:
+		viewState.ScrollOutput(page)
+	case event.Rune == 'b':
+		viewState.ScrollOutput(-page)
+	case event.Rune == 'g':
+		viewState.ScrollOutput(-viewState.GetOutputScroll())
+	case event.Rune == 'G':
+		// 마지막 줄이 화면 맨 아래에 오도록
+		_, lines := viewState.GetOutput()
+		viewState.ScrollOutput(max(len(lines)-page, 0) - viewState.GetOutputScroll())
+	case event.Rune == 'o' || event.Rune == 'q' || event.Key == terminal.KeyEsc || event.Key == terminal.KeyCtrlC:
+		viewState.SetMode(state.ViewModeNormal)
+	}
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/minimal1/twf-clone/internal/fileops"
+	"github.com/minimal1/twf-clone/internal/filetree"
+	"github.com/minimal1/twf-clone/internal/jobs"
+	"github.com/minimal1/twf-clone/internal/state"
+	"github.com/minimal1/twf-clone/internal/trash"
+)
+
+// 충돌 처리 방법을 묻는 중인 붙여넣기
+type pendingPaste struct {
+	items []fileops.Item
+	index int
+	move  bool
+}
+
+// 선택된 노드가 있으면 선택된 노드를, 없으면 커서 노드를 대상으로 함. 루트는 제외
+func (app *App) operationTargets() []*filetree.TreeNode {
+	root := app.filetree.GetRoot()
+
+	var targets []*filetree.TreeNode
+	selected := app.appState.Selection().GetSelectedNodes()
+	if len(selected) == 0 {
+		selected = []*filetree.TreeNode{app.appState.Cursor().GetCurrentNode()}
+	}
+
+	for _, node := range selected {
+		if node != nil && node != root {
+			targets = append(targets, node)
+		}
+	}
+	return targets
+}
+
+func (app *App) yank(cut bool) {
+	targets := app.operationTargets()
+	if len(targets) == 0 {
+		return
+	}
+
+	selection := app.appState.Selection()
+	verb := "Copied"
+	if cut {
+		selection.Cut(targets)
+		verb = "Cut"
+	} else {
+		selection.Copy(targets)
+	}
+	selection.ClearSelection()
+
+	app.appState.View().SetMessage(fmt.Sprintf("%s %d item(s)", verb, len(targets)))
+}
+
+// 클립보드 항목을 커서 위치의 디렉토리 (파일이면 상위 디렉토리)로 붙여넣음
+func (app *App) paste() {
+	clipboard := app.appState.Selection().GetClipboard()
+	if len(clipboard) == 0 {
+		app.appState.View().SetMessage("Clipboard is empty")
+		return
+	}
+
+	dir := app.appState.Cursor().GetCurrentNode()
+	if dir == nil {
+		app.appState.View().SetMessage("No directory to paste into")
+		return
+	}
+	if !dir.IsDir && dir.Parent != nil {
+		dir = dir.Parent
+	}
+
+	srcs := make([]string, len(clipboard))
+	for i, node := range clipboard {
+		srcs[i] = node.Path
+	}
+
+	app.pendingPaste = &pendingPaste{
+		items: fileops.Plan(srcs, dir.Path),
+		move:  app.appState.Selection().GetClipboardType() == state.ClipboardCut,
+	}
+	app.resolveConflicts()
+}
+
+// 다음 충돌 항목에서 사용자에게 묻고, 모두 정해졌으면 실행
+func (app *App) resolveConflicts() {
+	op := app.pendingPaste
+	viewState := app.appState.View()
+
+	for ; op.index < len(op.items); op.index++ {
+		item := op.items[op.index]
+		if !item.Exists {
+			continue
+		}
+		if item.Src == item.Dst && op.move {
+			// 같은 자리로 옮기는 것은 할 일이 없음
+			op.resolve(op.index, fileops.ConflictSkip)
+			continue
+		}
+
+		viewState.SetInputMode(state.InputModeResolveConflict)
+		viewState.SetPrompt(fmt.Sprintf(" %s exists: [s]kip [o]verwrite [r]ename (S/O/R: all)", filepath.Base(item.Dst)))
+		return
+	}
+
+	app.endInput()
+	app.runPaste(op)
+}
+
+func (app *App) handleConflictKey(r rune) {
+	op := app.pendingPaste
+	if op == nil {
+		app.endInput()
+		return
+	}
+
+	var conflict fileops.Conflict
+	switch r {
+	case 's', 'S':
+		conflict = fileops.ConflictSkip
+	case 'o', 'O':
+		conflict = fileops.ConflictOverwrite
+	case 'r', 'R':
+		conflict = fileops.ConflictRename
+	default:
+		return
+	}
+
+	// 대문자는 남은 충돌 모두에 적용
+	if r == 'S' || r == 'O' || r == 'R' {
+		for ; op.index < len(op.items); op.index++ {
+			op.resolve(op.index, conflict)
+		}
+	} else {
+		op.resolve(op.index, conflict)
+		op.index++
+	}
+
+	app.resolveConflicts()
+}
+
+// 충돌 처리 방법을 정해 대상 경로를 확정. 건너뛰면 Dst 가 빈 문자열
+func (op *pendingPaste) resolve(index int, conflict fileops.Conflict) {
+	item := &op.items[index]
+	if item.Exists {
+		item.Dst = fileops.Resolve(*item, conflict)
+		item.Exists = false
+	}
+}
+
+func (app *App) runPaste(op *pendingPaste) {
+	operation := state.Operation{Kind: state.OperationCopy, Timestamp: time.Now()}
+	if op.move {
+		operation.Kind = state.OperationMove
+		app.appState.Selection().ClearClipboard()
+	}
+
+	for _, item := range op.items {
+		if item.Dst == "" {
+			continue
+		}
+
+		change := state.FileChange{From: item.Src, To: item.Dst}
+		// 덮어쓸 항목은 되돌릴 수 있도록 먼저 휴지통으로 옮김
+		if _, err := os.Lstat(item.Dst); err == nil && item.Dst != item.Src {
+			trashed, err := trash.Prepare(item.Dst)
+			if err != nil {
+				app.appState.View().SetMessage(err.Error())
+				continue
+			}
+			change.Replaced = trashed
+		}
+		operation.Changes = append(operation.Changes, change)
+	}
+
+	app.runOperation(operation, historyRecord)
+}
+
+type deleteAction int
+
+const (
+	deleteToTrash deleteAction = iota
+	deletePermanently
+	deleteFromTrash
+)
+
+// confirmDelete 설정에 따라 확인을 받은 뒤 삭제
+func (app *App) confirmDelete(action deleteAction) {
+	var names []string
+	if action == deleteFromTrash {
+		item, ok := app.trashItemAtCursor()
+		if !ok {
+			return
+		}
+		names = append(names, item.OriginalPath)
+	} else {
+		for _, node := range app.operationTargets() {
+			names = append(names, node.Name)
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	if !app.appState.Config().GetConfirmDelete() {
+		app.delete(action)
+		return
+	}
+
+	verb := "Trash"
+	if action != deleteToTrash {
+		verb = "Delete permanently"
+	}
+
+	app.pendingDelete = action
+	viewState := app.appState.View()
+	viewState.SetInputMode(state.InputModeConfirmDelete)
+	if len(names) == 1 {
+		viewState.SetPrompt(fmt.Sprintf(" %s %s? [y/N]", verb, names[0]))
+	} else {
+		viewState.SetPrompt(fmt.Sprintf(" %s %d items? [y/N]", verb, len(names)))
+	}
+}
+
+func (app *App) handleConfirmDeleteKey(r rune) {
+	app.endInput()
+	if r == 'y' || r == 'Y' {
+		app.delete(app.pendingDelete)
+	}
+}
+
+func (app *App) delete(action deleteAction) {
+	switch action {
+	case deleteToTrash:
+		app.trashTargets()
+	case deletePermanently:
+		app.removeTargets()
+	case deleteFromTrash:
+		app.purgeTrashItem()
+	}
+}
+
+// 휴지통으로 옮김. 기록에 남아 되돌릴 수 있음
+func (app *App) trashTargets() {
+	operation := state.Operation{Kind: state.OperationDelete, Timestamp: time.Now()}
+	for _, node := range app.operationTargets() {
+		trashed, err := trash.Prepare(node.Path)
+		if err != nil {
+			app.appState.View().SetMessage(err.Error())
+			continue
+		}
+		operation.Changes = append(operation.Changes, state.FileChange{From: node.Path, To: trashed})
+	}
+
+	app.appState.Selection().ClearSelection()
+	app.runOperation(operation, historyRecord)
+}
+
+// 휴지통을 거치지 않고 바로 지움. 되돌릴 수 없음
+func (app *App) removeTargets() {
+	var tasks []jobs.Task
+	for _, node := range app.operationTargets() {
+		tasks = append(tasks, jobs.Task{Src: node.Path})
+	}
+
+	app.appState.Selection().ClearSelection()
+	app.startJob(jobs.Spec{Kind: jobs.KindDelete, Tasks: tasks})
+}
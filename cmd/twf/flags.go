@@ -0,0 +1,76 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/minimal1/twf-clone/internal/config"
+	"github.com/minimal1/twf-clone/internal/state"
+)
+
+// 빌드 시 -ldflags "-X main.version=..." 로 지정
+var version = "dev"
+
+type options struct {
+	config  *state.ConfigState
+	pick    bool
+	format  outputFormat
+	locate  string
+	version bool
+}
+
+func parseOptions(args []string, stderr io.Writer) (*options, error) {
+	flags := flag.NewFlagSet("twf", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	loader := config.NewLoader(flags)
+	configPath := flags.String("config", "", "load settings from `file` instead of $XDG_CONFIG_HOME/twf/config.{toml,yaml,json}")
+	pick := flags.Bool("pick", false, "print chosen paths to stdout on exit (default when stdout is not a terminal)")
+	print0 := flags.Bool("print0", false, "separate printed paths with NUL instead of newline")
+	printJSON := flags.Bool("json", false, "print chosen paths as a JSON array")
+	locate := flags.String("locate", "", "reveal `path` in the tree on startup")
+	showVersion := flags.Bool("version", false, "print version and exit")
+
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: twf [flags] [dir]\n\nFlags:\n")
+		flags.PrintDefaults()
+		fmt.Fprintf(stderr, "\nSettings are read from the config file, then TWF_<KEY> environment\n")
+		fmt.Fprintf(stderr, "variables (e.g. TWF_HIDDEN=1), then flags; later sources win.\n")
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	opts := &options{
+		config:  state.NewConfigState(),
+		pick:    *pick,
+		locate:  *locate,
+		version: *showVersion,
+	}
+	if opts.version {
+		return opts, nil
+	}
+
+	if *print0 {
+		opts.format = outputNull
+	}
+	if *printJSON {
+		opts.format = outputJSON
+	}
+
+	if err := loader.Load(opts.config, *configPath); err != nil {
+		return nil, err
+	}
+
+	// 위치 인자는 -dir 과 같음
+	if flags.NArg() > 1 {
+		return nil, fmt.Errorf("too many arguments: %v", flags.Args())
+	}
+	if flags.NArg() == 1 {
+		opts.config.SetDefaultPath(flags.Arg(0))
+	}
+
+	return opts, nil
+}
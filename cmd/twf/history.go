@@ -0,0 +1,414 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/minimal1/twf-clone/internal/fileops"
+	"github.com/minimal1/twf-clone/internal/jobs"
+	"github.com/minimal1/twf-clone/internal/state"
+	"github.com/minimal1/twf-clone/internal/trash"
+)
+
+type historyAction int
+
+const (
+	historyRecord historyAction = iota
+	historyUndo
+	historyRedo
+)
+
+// 작업이 끝났을 때 기록에 반영할 내용
+type historyIntent struct {
+	action historyAction
+	op     state.Operation
+	spec   jobs.Spec
+}
+
+// 작업을 백그라운드로 실행하고, 끝나면 action 에 따라 기록
+func (app *App) runOperation(op state.Operation, action historyAction) {
+	app.startOperation(op, forwardSpec(op), action)
+}
+
+func (app *App) startOperation(op state.Operation, spec jobs.Spec, action historyAction) {
+	if len(spec.Tasks) == 0 {
+		return
+	}
+
+	job := app.startJob(spec)
+	app.jobIntents[job.ID] = historyIntent{action: action, op: op, spec: spec}
+}
+
+func forwardSpec(op state.Operation) jobs.Spec {
+	spec := jobs.Spec{}
+	switch op.Kind {
+	case state.OperationCopy:
+		spec.Kind = jobs.KindCopy
+	case state.OperationMove:
+		spec.Kind = jobs.KindMove
+	case state.OperationDelete:
+		spec.Kind = jobs.KindDelete
+	case state.OperationChmod:
+		spec.Kind = jobs.KindChmod
+	}
+
+	for _, change := range op.Changes {
+		spec.Tasks = append(spec.Tasks, jobs.Task{Src: change.From, Dst: change.To, Mode: change.NewMode, Backup: change.Replaced})
+	}
+	return spec
+}
+
+// 작업을 되돌리는 작업. 복사본은 휴지통으로, 이동과 삭제는 원래 자리로 옮기고 덮어쓴 항목을 되살림.
+// 복사본을 옮길 휴지통 경로는 prepareTrash 로 채움
+func inverseSpec(op state.Operation) jobs.Spec {
+	spec := jobs.Spec{}
+	for _, change := range op.Changes {
+		switch op.Kind {
+		case state.OperationCopy:
+			spec.Kind = jobs.KindDelete
+			spec.Tasks = append(spec.Tasks, jobs.Task{Src: change.To, Restore: change.Replaced})
+		case state.OperationMove, state.OperationDelete:
+			spec.Kind = jobs.KindMove
+			spec.Tasks = append(spec.Tasks, jobs.Task{Src: change.To, Dst: change.From, Restore: change.Replaced})
+		case state.OperationChmod:
+			spec.Kind = jobs.KindChmod
+			spec.Tasks = append(spec.Tasks, jobs.Task{Src: change.From, Mode: change.OldMode})
+		}
+	}
+	return spec
+}
+
+// 각 작업의 Src 를 옮길 휴지통 경로를 Dst 에 채움. 실패하면 이미 만든 .trashinfo 를 지움
+func prepareTrash(tasks []jobs.Task) error {
+	for i := range tasks {
+		trashed, err := trash.Prepare(tasks[i].Src)
+		if err != nil {
+			for _, task := range tasks[:i] {
+				trash.Forget(task.Dst)
+			}
+			return err
+		}
+		tasks[i].Dst = trashed
+	}
+	return nil
+}
+
+// 끝난 작업에서 성공한 변경만 기록에 반영. 실패한 나머지는 원래 스택에 남김
+func (app *App) recordJob(job *jobs.Job) {
+	intent, ok := app.jobIntents[job.ID]
+	if !ok {
+		return
+	}
+	delete(app.jobIntents, job.ID)
+
+	done, rest := splitChanges(intent.op, job.Completed())
+	history := app.appState.History()
+
+	if intent.op.Kind == state.OperationDelete {
+		// 휴지통에서 꺼냈거나 옮기지 못한 항목의 .trashinfo 정리
+		forget := rest.Changes
+		if intent.action == historyUndo {
+			forget = done.Changes
+		}
+		for _, change := range forget {
+			trash.Forget(change.To)
+		}
+	}
+
+	// 덮어쓰기 전에 옮겨 두려던 항목이나 되돌리며 버린 복사본 중 휴지통에 없는 것의 .trashinfo 정리
+	for _, change := range intent.op.Changes {
+		forgetUnused(change.Replaced)
+	}
+	if intent.op.Kind == state.OperationCopy && intent.action == historyUndo {
+		for _, task := range intent.spec.Tasks {
+			forgetUnused(task.Dst)
+		}
+	}
+
+	// 되돌리기 전에 바뀌었는지 확인할 수 있도록 복사본의 상태를 남김
+	if intent.op.Kind == state.OperationCopy && intent.action != historyUndo {
+		for i := range done.Changes {
+			change := &done.Changes[i]
+			change.Size, change.ModTime, _ = fileops.Stamp(change.To)
+		}
+	}
+
+	switch intent.action {
+	case historyRecord:
+		if len(done.Changes) > 0 {
+			history.Record(done)
+		}
+	case historyUndo:
+		if len(done.Changes) > 0 {
+			history.PushRedo(done)
+		}
+		if len(rest.Changes) > 0 {
+			history.PushUndo(rest)
+		}
+	case historyRedo:
+		if len(done.Changes) > 0 {
+			history.PushUndo(done)
+		}
+		if len(rest.Changes) > 0 {
+			history.PushRedo(rest)
+		}
+	}
+}
+
+func splitChanges(op state.Operation, completed []int) (done, rest state.Operation) {
+	done = state.Operation{Kind: op.Kind, Timestamp: op.Timestamp}
+	rest = done
+
+	finished := make(map[int]bool, len(completed))
+	for _, i := range completed {
+		finished[i] = true
+	}
+
+	for i, change := range op.Changes {
+		if finished[i] {
+			done.Changes = append(done.Changes, change)
+		} else {
+			rest.Changes = append(rest.Changes, change)
+		}
+	}
+	return done, rest
+}
+
+func (app *App) undo() {
+	history := app.appState.History()
+	op, ok := history.PeekUndo()
+	if !ok {
+		app.appState.View().SetMessage("Nothing to undo")
+		return
+	}
+
+	if op.Kind == state.OperationRename {
+		app.undoRename(op, historyUndo)
+		return
+	}
+
+	if err := checkUndo(op); err != nil {
+		app.appState.View().SetMessage(fmt.Sprintf("Cannot undo %s: %v", op.Kind, err))
+		return
+	}
+
+	spec := inverseSpec(op)
+	if op.Kind == state.OperationCopy {
+		if err := prepareTrash(spec.Tasks); err != nil {
+			app.appState.View().SetMessage(fmt.Sprintf("Cannot undo %s: %v", op.Kind, err))
+			return
+		}
+	}
+
+	history.PopUndo()
+	app.startOperation(op, spec, historyUndo)
+	app.appState.View().SetMessage(fmt.Sprintf("Undo %s", op.Kind))
+}
+
+func (app *App) redo() {
+	history := app.appState.History()
+	op, ok := history.PeekRedo()
+	if !ok {
+		app.appState.View().SetMessage("Nothing to redo")
+		return
+	}
+
+	if op.Kind == state.OperationRename {
+		app.undoRename(op, historyRedo)
+		return
+	}
+
+	if err := checkRedo(op); err != nil {
+		app.appState.View().SetMessage(fmt.Sprintf("Cannot redo %s: %v", op.Kind, err))
+		return
+	}
+
+	if op.Kind == state.OperationDelete {
+		for _, change := range op.Changes {
+			if err := trash.Reserve(change.To, change.From); err != nil {
+				app.appState.View().SetMessage(err.Error())
+				return
+			}
+		}
+	}
+
+	if op.Kind == state.OperationCopy || op.Kind == state.OperationMove {
+		var err error
+		if op, err = replaceTargets(op); err != nil {
+			app.appState.View().SetMessage(fmt.Sprintf("Cannot redo %s: %v", op.Kind, err))
+			return
+		}
+	}
+
+	history.PopRedo()
+	app.runOperation(op, historyRedo)
+	app.appState.View().SetMessage(fmt.Sprintf("Redo %s", op.Kind))
+}
+
+// 이름 변경은 교환이나 순환이 있을 수 있어 작업 대신 바로 한꺼번에 적용
+func (app *App) undoRename(op state.Operation, action historyAction) {
+	verb := "Undo"
+	if action == historyRedo {
+		verb = "Redo"
+	}
+
+	if err := applyRenameOperation(op, action); err != nil {
+		app.appState.View().SetMessage(fmt.Sprintf("Cannot %s %s: %v", strings.ToLower(verb), op.Kind, err))
+		return
+	}
+
+	history := app.appState.History()
+	if action == historyUndo {
+		history.PopUndo()
+		history.PushRedo(op)
+	} else {
+		history.PopRedo()
+		history.PushUndo(op)
+	}
+	app.appState.View().SetMessage(fmt.Sprintf("%s %s", verb, op.Kind))
+	app.refreshAll()
+}
+
+// 다시 실행할 때 대상 자리에 있는 항목 (되돌리며 되살린 항목)을 휴지통으로 옮길 경로를 정함
+func replaceTargets(op state.Operation) (state.Operation, error) {
+	changes := make([]state.FileChange, len(op.Changes))
+	for i, change := range op.Changes {
+		change.Replaced = ""
+		change.Size, change.ModTime = 0, time.Time{}
+		if _, err := os.Lstat(change.To); err == nil {
+			trashed, err := trash.Prepare(change.To)
+			if err != nil {
+				for _, prepared := range changes[:i] {
+					forgetUnused(prepared.Replaced)
+				}
+				return op, err
+			}
+			change.Replaced = trashed
+		}
+		changes[i] = change
+	}
+	op.Changes = changes
+	return op, nil
+}
+
+// 작업에 쓰려고 만든 휴지통 경로에 항목이 없으면 .trashinfo 를 지움
+func forgetUnused(trashed string) {
+	if trashed == "" {
+		return
+	}
+	if _, err := os.Lstat(trashed); errors.Is(err, os.ErrNotExist) {
+		trash.Forget(trashed)
+	}
+}
+
+// 기록한 뒤 파일 시스템이 바뀌었으면 되돌리지 않음
+func checkUndo(op state.Operation) error {
+	for _, change := range op.Changes {
+		switch op.Kind {
+		case state.OperationCopy:
+			if err := mustExist(change.To); err != nil {
+				return err
+			}
+			if err := mustBeUnchanged(change); err != nil {
+				return err
+			}
+			if err := mustExistIfSet(change.Replaced); err != nil {
+				return err
+			}
+		case state.OperationMove, state.OperationDelete:
+			if err := mustExist(change.To); err != nil {
+				return err
+			}
+			if err := mustNotExist(change.From); err != nil {
+				return err
+			}
+			if err := mustExistIfSet(change.Replaced); err != nil {
+				return err
+			}
+		case state.OperationChmod:
+			if err := mustHaveMode(change.From, change.NewMode); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkRedo(op state.Operation) error {
+	for _, change := range op.Changes {
+		switch op.Kind {
+		case state.OperationCopy, state.OperationMove:
+			// 덮어썼던 항목은 되돌리며 제자리에 돌아와 있음
+			if err := mustExist(change.From); err != nil {
+				return err
+			}
+			if change.Replaced == "" {
+				if err := mustNotExist(change.To); err != nil {
+					return err
+				}
+			}
+		case state.OperationDelete:
+			if err := mustExist(change.From); err != nil {
+				return err
+			}
+			if err := mustNotExist(change.To); err != nil {
+				return err
+			}
+		case state.OperationChmod:
+			if err := mustHaveMode(change.From, change.OldMode); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func mustExist(path string) error {
+	if _, err := os.Lstat(path); err != nil {
+		return fmt.Errorf("%s no longer exists", path)
+	}
+	return nil
+}
+
+func mustExistIfSet(path string) error {
+	if path == "" {
+		return nil
+	}
+	return mustExist(path)
+}
+
+// 복사한 뒤 복사본을 고쳤으면 휴지통으로 옮기지 않음
+func mustBeUnchanged(change state.FileChange) error {
+	if change.ModTime.IsZero() {
+		return nil
+	}
+	size, modTime, err := fileops.Stamp(change.To)
+	if err != nil {
+		return fmt.Errorf("%s no longer exists", change.To)
+	}
+	if size != change.Size || !modTime.Equal(change.ModTime) {
+		return fmt.Errorf("%s has changed since it was copied", change.To)
+	}
+	return nil
+}
+
+func mustNotExist(path string) error {
+	if _, err := os.Lstat(path); !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%s already exists", path)
+	}
+	return nil
+}
+
+func mustHaveMode(path string, mode os.FileMode) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return fmt.Errorf("%s no longer exists", path)
+	}
+	if info.Mode().Perm() != mode {
+		return fmt.Errorf("mode of %s has changed", path)
+	}
+	return nil
+}
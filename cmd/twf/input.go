@@ -0,0 +1,156 @@
+package main
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/minimal1/twf-clone/internal/state"
+	"github.com/minimal1/twf-clone/internal/terminal"
+)
+
+func isTextInputMode(mode state.InputMode) bool {
+	switch mode {
+	case state.InputModeFilter, state.InputModeSearch, state.InputModeChmod, state.InputModeCommand:
+		return true
+	}
+	return false
+}
+
+// 필터/검색처럼 텍스트를 입력받는 모드의 키 처리. 처리했으면 true
+func (app *App) handleTextInputKey(event terminal.KeyPressEvent) bool {
+	viewState := app.appState.View()
+	mode := viewState.GetInputMode()
+	if !isTextInputMode(mode) {
+		return false
+	}
+
+	switch {
+	case event.Rune != 0:
+		viewState.AppendInput(string(event.Rune))
+	case event.Key == terminal.KeyBackspace:
+		viewState.DeleteInputChar()
+	case event.Key == terminal.KeyEnter:
+		app.submitInput(mode)
+		return true
+	case event.Key == terminal.KeyEsc || event.Key == terminal.KeyCtrlC:
+		app.cancelInput(mode)
+		return true
+	case event.Key == terminal.KeyArrowDown || event.Key == terminal.KeyTab:
+		if mode == state.InputModeSearch {
+			app.moveSearchIndex(1)
+			app.updateSearchPrompt()
+		}
+		return true
+	case event.Key == terminal.KeyArrowUp:
+		if mode == state.InputModeSearch {
+			app.moveSearchIndex(-1)
+			app.updateSearchPrompt()
+		}
+		return true
+	default:
+		return true
+	}
+
+	app.updateInput(mode)
+	return true
+}
+
+// 입력 중이면 붙여넣은 텍스트를 그대로 넣고, 일반 모드에서 절대 경로를 붙여넣으면 트리에서 보여 줌
+func (app *App) handlePaste(text string) {
+	viewState := app.appState.View()
+	viewState.ClearMessage()
+
+	mode := viewState.GetInputMode()
+	if isTextInputMode(mode) {
+		// 프롬프트는 한 줄이므로 줄바꿈은 공백으로
+		text = strings.TrimRight(text, "\n")
+		viewState.AppendInput(strings.ReplaceAll(text, "\n", " "))
+		app.updateInput(mode)
+		return
+	}
+
+	if mode != state.InputModeNormal || viewState.GetMode() != state.ViewModeNormal {
+		return
+	}
+
+	path := pastedPath(text)
+	if !filepath.IsAbs(path) {
+		return
+	}
+	if err := app.locate(path); err != nil {
+		viewState.SetMessage(err.Error())
+		return
+	}
+	viewState.ClearFilter()
+	app.ensureCursorVisible()
+}
+
+// 파일 관리자에서 끌어다 놓으면 따옴표나 file:// 이 붙어 올 수 있음
+func pastedPath(text string) string {
+	path := strings.TrimSpace(text)
+	if len(path) >= 2 && (path[0] == '\'' || path[0] == '"') && path[len(path)-1] == path[0] {
+		path = path[1 : len(path)-1]
+	}
+	if rest, ok := strings.CutPrefix(path, "file://"); ok {
+		if unescaped, err := url.PathUnescape(rest); err == nil {
+			path = unescaped
+		}
+	}
+	return filepath.Clean(path)
+}
+
+func (app *App) updateInput(mode state.InputMode) {
+	switch mode {
+	case state.InputModeFilter:
+		app.updateFilter()
+	case state.InputModeSearch:
+		app.updateSearch()
+	case state.InputModeChmod:
+		app.updateChmodPrompt()
+	case state.InputModeCommand:
+		app.updateCommandPrompt()
+	}
+}
+
+func (app *App) submitInput(mode state.InputMode) {
+	switch mode {
+	case state.InputModeFilter:
+		app.endInput()
+	case state.InputModeSearch:
+		app.confirmSearch()
+	case state.InputModeChmod:
+		app.submitChmod()
+	case state.InputModeCommand:
+		app.submitCommand()
+	}
+}
+
+func (app *App) cancelInput(mode state.InputMode) {
+	switch mode {
+	case state.InputModeFilter:
+		app.appState.View().ClearFilter()
+		app.endInput()
+	case state.InputModeSearch:
+		app.cancelSearch()
+	case state.InputModeChmod, state.InputModeCommand:
+		app.endInput()
+	}
+}
+
+func (app *App) endInput() {
+	app.pendingPaste = nil
+
+	viewState := app.appState.View()
+	viewState.SetInputMode(state.InputModeNormal)
+	viewState.SetInputText("")
+	viewState.ClearPrompt()
+}
+
+// 입력할 때마다 필터를 바로 적용
+func (app *App) updateFilter() {
+	viewState := app.appState.View()
+	viewState.SetFilter(viewState.GetInputText())
+	viewState.SetPrompt(" Filter: " + viewState.GetInputText() + "_")
+	app.ensureCursorVisible()
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/minimal1/twf-clone/internal/jobs"
+	"github.com/minimal1/twf-clone/internal/state"
+	"github.com/minimal1/twf-clone/internal/terminal"
+)
+
+const jobsRefreshInterval = 100 * time.Millisecond
+
+// 파일 작업을 백그라운드에서 시작. 진행 상황은 jobsTick 마다 반영
+func (app *App) startJob(spec jobs.Spec) *jobs.Job {
+	job := app.jobs.Start(spec)
+	app.setJobs(app.jobs.Snapshots())
+
+	if app.jobsTicker == nil {
+		app.jobsTicker = time.NewTicker(jobsRefreshInterval)
+	}
+	return job
+}
+
+func (app *App) stopJobs() {
+	app.jobs.CancelAll()
+	app.jobs.Wait()
+
+	// 취소되어 옮기지 못한 항목의 .trashinfo 를 정리하도록 끝난 작업을 마저 반영
+	finished, _ := app.jobs.TakeFinished()
+	for _, job := range finished {
+		app.finishJob(job)
+	}
+
+	if app.jobsTicker != nil {
+		app.jobsTicker.Stop()
+		app.jobsTicker = nil
+	}
+}
+
+func (app *App) jobsTick() <-chan time.Time {
+	if app.jobsTicker == nil {
+		return nil
+	}
+	return app.jobsTicker.C
+}
+
+func (app *App) handleJobsTick() {
+	finished, running := app.jobs.TakeFinished()
+	app.setJobs(app.jobs.Snapshots())
+
+	if len(finished) > 0 {
+		app.refreshAll()
+	}
+	for _, job := range finished {
+		app.finishJob(job)
+
+		snapshot := job.Snapshot()
+		switch snapshot.Status {
+		case jobs.StatusFailed:
+			app.appState.View().SetMessage(fmt.Sprintf("%s %s: %v", snapshot.Kind, snapshot.Title, snapshot.Errors[0].Err))
+		default:
+			app.appState.View().SetMessage(fmt.Sprintf("%s %s: %s", snapshot.Kind, snapshot.Title, snapshot.Status))
+		}
+	}
+
+	if running == 0 {
+		app.jobsTicker.Stop()
+		app.jobsTicker = nil
+	}
+}
+
+func (app *App) finishJob(job *jobs.Job) {
+	app.recordJob(job)
+	app.finishRestore(job)
+}
+
+func (app *App) cancelRunningJobs() {
+	if app.jobs.Running() == 0 {
+		return
+	}
+	app.jobs.CancelAll()
+	app.appState.View().SetMessage("Cancelling jobs…")
+}
+
+func (app *App) toggleJobsView() {
+	viewState := app.appState.View()
+	if viewState.GetMode() == state.ViewModeJobs {
+		viewState.SetMode(state.ViewModeNormal)
+		return
+	}
+
+	app.setJobs(app.jobs.Snapshots())
+	viewState.SetMode(state.ViewModeJobs)
+}
+
+func (app *App) setJobs(snapshots []jobs.Snapshot) {
+	app.jobList = snapshots
+	app.jobIndex = min(app.jobIndex, max(len(snapshots)-1, 0))
+}
+
+func (app *App) moveJobIndex(delta int) {
+	if len(app.jobList) == 0 {
+		return
+	}
+	app.jobIndex = min(max(app.jobIndex+delta, 0), len(app.jobList)-1)
+}
+
+// 실행 중인 작업 중 가장 먼저 시작한 것
+func (app *App) runningJob() (jobs.Snapshot, bool) {
+	for _, job := range app.jobList {
+		if job.Status == jobs.StatusRunning {
+			return job, true
+		}
+	}
+	return jobs.Snapshot{}, false
+}
+
+// jobs 뷰에서의 키 처리
+func (app *App) handleJobsKey(event terminal.KeyPressEvent) {
+	viewState := app.appState.View()
+
+	switch {
+	case event.Rune == 'j' || event.Key == terminal.KeyArrowDown:
+		app.moveJobIndex(1)
+	case event.Rune == 'k' || event.Key == terminal.KeyArrowUp:
+		app.moveJobIndex(-1)
+	case event.Rune == 'x':
+		if len(app.jobList) == 0 {
+			return
+		}
+		if job := app.jobs.Get(app.jobList[app.jobIndex].ID); job != nil {
+			job.Cancel()
+		}
+	case event.Rune == 'J' || event.Rune == 'q' || event.Key == terminal.KeyEsc || event.Key == terminal.KeyCtrlC:
+		viewState.SetMode(state.ViewModeNormal)
+	}
+}
+
+func (app *App) startChmod() {
+	if len(app.operationTargets()) == 0 {
+		return
+	}
+
+	viewState := app.appState.View()
+	viewState.SetInputMode(state.InputModeChmod)
+	viewState.SetInputText("")
+	app.updateChmodPrompt()
+}
+
+func (app *App) updateChmodPrompt() {
+	app.appState.View().SetPrompt(" Chmod (octal): " + app.appState.View().GetInputText() + "_")
+}
+
+func (app *App) submitChmod() {
+	text := app.appState.View().GetInputText()
+	app.endInput()
+
+	mode, err := strconv.ParseUint(text, 8, 32)
+	if err != nil || mode > 0o777 {
+		app.appState.View().SetMessage(fmt.Sprintf("invalid mode %q", text))
+		return
+	}
+
+	operation := state.Operation{Kind: state.OperationChmod, Timestamp: time.Now()}
+	for _, node := range app.operationTargets() {
+		info, err := os.Lstat(node.Path)
+		if err != nil {
+			continue
+		}
+		operation.Changes = append(operation.Changes, state.FileChange{
+			From:    node.Path,
+			OldMode: info.Mode().Perm(),
+			NewMode: os.FileMode(mode),
+		})
+	}
+	app.runOperation(operation, historyRecord)
+}
@@ -1,80 +1,217 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
-	"os/signal"
-	"syscall"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/minimal1/twf-clone/internal/filetree"
+	"github.com/minimal1/twf-clone/internal/ignore"
+	"github.com/minimal1/twf-clone/internal/jobs"
+	"github.com/minimal1/twf-clone/internal/preview"
+	"github.com/minimal1/twf-clone/internal/search"
 	"github.com/minimal1/twf-clone/internal/state"
 	"github.com/minimal1/twf-clone/internal/terminal"
+	"github.com/minimal1/twf-clone/internal/trash"
 	"github.com/minimal1/twf-clone/internal/views"
 )
 
 type App struct {
-	term     *terminal.Terminal
-	filetree *filetree.FileTreeImpl
-	walker   *filetree.Walker
-	appState *state.AppState
-	running  bool
-
+	term          *terminal.Terminal
+	ignoreMatcher *ignore.Matcher
+	filetree      *filetree.FileTreeImpl
+	watcher       *filetree.Watcher
+	walker        *filetree.Walker
+	appState      *state.AppState
+	running       bool
+
+	// pick 모드: 선택한 경로를 stdout 으로 출력하고 종료
+	pickMode bool
+	picked   []string
+
+	pendingPaste  *pendingPaste
+	pendingDelete deleteAction
+
+	trash *trashBrowser
+
+	index       *search.Index
+	indexTicker *time.Ticker
+	// 감시 이벤트로 경로가 바뀌어 다시 인덱싱해야 함
+	indexStale   bool
+	indexStarted time.Time
+
+	// 검색 결과는 ranker 에서 받음. ranked 는 지금 보여 주는 결과, searchIndex 는 그중 선택한 위치
+	ranker      *search.Ranker
+	ranked      search.Ranking
+	rankPending bool
+	searchIndex int
+
+	jobs       *jobs.Manager
+	jobsTicker *time.Ticker
+	// jobs 뷰와 상태바에 보여 줄 작업 목록
+	jobList    []jobs.Snapshot
+	jobIndex   int
+	jobIntents map[int]historyIntent
+	// 휴지통에서 되돌리는 작업
+	restoreJobs map[int]trash.Item
+
+	// : 로 실행한 명령은 commandResults 로 결과를 받고, 종료할 때 취소
+	commandWait    bool
+	commandResults chan commandResult
+	commandCtx     context.Context
+	cancelCommands context.CancelFunc
+
+	preview     *preview.Manager
+	previewView *views.PreviewView
+	searchView  *views.SearchView
+	jobsView    *views.JobsView
+	statusView  *views.StatusView
+	previewKey  string
+
+	// 휠로 미리보기를 내리면 화면보다 더 많은 줄을 읽음
+	previewPath  string
+	previewExtra int
+
+	// Ctrl-Z 로 멈춘 뒤 SIGCONT 로 오는 ResumeEvent 를 아직 받지 않음
+	suspended bool
+
+	// 더블 클릭 판정용
+	lastClickNode *filetree.TreeNode
+	lastClickTime time.Time
+
+	// 그릴 영역. inline 모드(-height)에서는 화면 아래쪽 일부만 사용
 	layout *views.Layout
+	screen *terminal.Screen
 	width  int
 	height int
+	top    int
+	inline bool
 }
 
-func NewApp(startPath string) (*App, error) {
+const previewTimeout = 3 * time.Second
+
+func NewApp(config *state.ConfigState) (*App, error) {
 	term, termErr := terminal.NewTerminal()
 	if termErr != nil {
 		return nil, termErr
 	}
 
 	ft := filetree.NewFileTree()
-	ftErr := ft.LoadRoot(startPath)
+	ftErr := ft.LoadRoot(config.GetDefaultPath())
 	if ftErr != nil {
 		term.Cleanup()
 		return nil, ftErr
 	}
 
+	root := ft.GetRoot()
+	ignoreMatcher := ignore.NewMatcher(root.Path, config.GetIgnoreGlobs())
+	ft.SetIgnore(ignoreMatcher.Ignored)
+	ft.SetFollowSymlinks(config.GetFollowSymlinks())
+
+	// 감시를 지원하지 않는 환경에서는 수동 새로고침만 사용
+	watcher, err := filetree.NewWatcher(watchDebounce)
+	if err == nil {
+		ft.SetWatcher(watcher)
+	}
+
 	walker := filetree.NewWalker(ft)
 
-	appState := state.NewAppState()
+	appState := state.NewAppStateWithConfig(config)
 	appState.Initialize(ft.GetRoot())
+	ft.SetSortOptions(appState.View().SortOptions())
+
+	var previewer preview.Previewer
+	if command := config.GetPreviewCommand(); command != "" {
+		previewer = preview.NewCommandPreviewer(command, previewTimeout)
+	}
 
 	return &App{
-		term:     term,
-		filetree: ft,
-		walker:   walker,
-		appState: appState,
-		running:  false,
+		term:          term,
+		screen:        terminal.NewScreen(term),
+		ignoreMatcher: ignoreMatcher,
+		filetree:      ft,
+		watcher:       watcher,
+		walker:        walker,
+		appState:      appState,
+		running:       false,
+		preview:       preview.NewManager(previewer),
+		ranker:        search.NewRanker(),
+		jobs:          jobs.NewManager(),
+		jobIntents:    make(map[int]historyIntent),
+		restoreJobs:   make(map[int]trash.Item),
+		trash:         newTrashBrowser(),
 	}, nil
 }
 
 func (app *App) Cleanup() {
+	if app.watcher != nil {
+		app.watcher.Close()
+	}
 	if app.term != nil {
 		app.term.Cleanup()
 	}
 }
 
 func main() {
-	startPath := "."
-	if len(os.Args) > 1 {
-		startPath = os.Args[1]
+	os.Exit(run())
+}
+
+func run() int {
+	opts, err := parseOptions(os.Args[1:], os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "twf: %v\n", err)
+		return exitUsage
 	}
 
-	app, err := NewApp(startPath)
+	if opts.version {
+		fmt.Printf("twf %s\n", version)
+		return exitOK
+	}
+
+	app, err := NewApp(opts.config)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to initialize app: %v\n", err)
-		os.Exit(1)
+		return exitError
 	}
 
 	defer app.Cleanup()
 
+	app.pickMode = opts.pick || !terminal.IsTerminal(os.Stdout)
+
+	if opts.locate != "" {
+		if err := app.locate(opts.locate); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to locate %s: %v\n", opts.locate, err)
+			return exitError
+		}
+	}
+
 	if err := app.Run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %V\n", err)
-		os.Exit(1)
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+
+	if !app.pickMode {
+		return exitOK
+	}
+
+	if app.picked == nil {
+		return exitCancelled
 	}
+
+	if err := writePaths(os.Stdout, app.picked, opts.format); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return exitError
+	}
+
+	return exitOK
 }
 
 func (app *App) Run() error {
@@ -84,25 +221,32 @@ func (app *App) Run() error {
 
 	defer app.term.DisableRawMode()
 
-	app.term.EnterAltScreen()
-	defer app.term.ExitAltScreen()
-
-	app.term.ClearScreen()
-	app.term.HideCursor()
-	defer app.term.ShowCursor()
-
-	app.width, app.height, _ = app.term.GetSize()
+	app.enterScreen()
+	defer app.exitScreen()
 
 	treeView := views.NewTreeView(app.walker)
-	statusView := views.StatusView{}
-	app.layout = views.NewLayout(treeView, &statusView)
+	app.statusView = &views.StatusView{}
+	app.previewView = views.NewPreviewView()
+	app.searchView = views.NewSearchView()
+	app.jobsView = views.NewJobsView()
+	app.layout = views.NewLayout(treeView, app.statusView, app.previewView, app.searchView, app.jobsView, views.NewTreeView(app.trash.walker), views.NewOutputView())
 	app.layout.SetSize(app.width, app.height)
+	app.layout.SetOrigin(app.top)
+
+	defer app.preview.Cancel()
+
+	app.startIndex()
+	defer app.stopIndex()
+	defer app.ranker.Cancel()
+	defer app.stopJobs()
 
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGWINCH)
-	defer signal.Stop(sigCh)
+	app.commandCtx, app.cancelCommands = context.WithCancel(context.Background())
+	app.commandResults = make(chan commandResult)
+	defer app.cancelCommands()
 
-	if err := app.layout.Render(app.term, app.appState); err != nil {
+	app.adjustScroll(app.height)
+	app.requestPreview()
+	if err := app.render(); err != nil {
 		return err
 	}
 
@@ -110,36 +254,134 @@ func (app *App) Run() error {
 
 	for app.running {
 		select {
-		case <-sigCh:
-			// 터미널 리사이즈 처리
-			app.handleResize()
-		default:
-			event, err := app.term.ReadEvent()
-			if err != nil {
-				return err
+		case event := <-app.term.Events():
+			if e, ok := event.(terminal.ErrorEvent); ok {
+				return e.Err
 			}
 
 			app.handleEvent(event)
 			app.adjustScroll(app.height)
-
-			app.term.ClearScreen()
-			if err := app.layout.Render(app.term, app.appState); err != nil {
-				return err
+			app.requestPreview()
+		case result := <-app.preview.Results():
+			if app.preview.IsCurrent(result) {
+				app.previewView.SetContent(result)
 			}
+		case events := <-app.watchEvents():
+			app.handleWatchEvents(events)
+			app.adjustScroll(app.height)
+			app.requestPreview()
+		case <-app.jobsTick():
+			app.handleJobsTick()
+			app.adjustScroll(app.height)
+			app.requestPreview()
+		case result := <-app.commandResults:
+			app.handleCommandResult(result)
+			app.adjustScroll(app.height)
+			app.requestPreview()
+		case <-app.indexTick():
+			app.handleIndexTick()
+			app.requestPreview()
+		case ranking := <-app.ranker.Results():
+			app.handleRanking(ranking)
+			app.adjustScroll(app.height)
+			app.requestPreview()
+		}
+
+		if err := app.render(); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
+// 커서 노드나 미리보기 영역이 바뀌었을 때만 새 미리보기를 요청
+func (app *App) requestPreview() {
+	path, _, isDir, ok := views.PreviewTarget(app.appState, app.selectedSearchResult())
+	if !ok || !app.layout.PreviewVisible() {
+		app.preview.Cancel()
+		app.previewKey = ""
+		return
+	}
+
+	if path != app.previewPath {
+		app.previewPath = path
+		app.previewExtra = 0
+	}
+
+	rect := app.layout.PreviewRect()
+	height := rect.Height - 1 + app.previewExtra
+	showHidden := app.appState.View().ShowHidden()
+	key := fmt.Sprintf("%s:%dx%d:%t", path, rect.Width, height, showHidden)
+	if key == app.previewKey {
+		return
+	}
+	app.previewKey = key
+
+	app.preview.Request(preview.Request{
+		Path:        path,
+		IsDir:       isDir,
+		Width:       rect.Width,
+		Height:      height,
+		LineNumbers: app.appState.Config().GetShowLineNumbers(),
+		ShowHidden:  showHidden,
+	})
+}
+
 func (app *App) handleEvent(event terminal.Event) {
 	switch e := event.(type) {
 	case terminal.KeyPressEvent:
 		app.handleKeyPress(e)
+	case terminal.MouseEvent:
+		app.handleMouse(e)
+	case terminal.PasteEvent:
+		app.handlePaste(e.Text)
+	case terminal.ResizeEvent:
+		app.handleResize()
+	case terminal.SuspendEvent:
+		app.suspend()
+	case terminal.ResumeEvent:
+		app.resumeScreen()
+	case terminal.ModeReportEvent:
+		// 동기화 출력을 지원하는 터미널에서만 화면 갱신을 묶음
+		if e.Mode == terminal.SyncOutputMode && (e.Value == 1 || e.Value == 2) {
+			app.screen.SetSyncOutput(true)
+		}
+	case terminal.FocusEvent:
+		// 감시를 쓸 수 없으면 창으로 돌아올 때 새로고침
+		if e.Focused && app.watcher == nil {
+			app.refreshAll()
+		}
+	case terminal.InterruptEvent:
+		// 루프를 빠져나가 Run 의 defer 에서 터미널을 되돌림
+		app.running = false
 	}
 }
 
 func (app *App) handleKeyPress(event terminal.KeyPressEvent) {
+	app.appState.View().ClearMessage()
+
+	// Alt/Ctrl 과 함께 누른 문자는 아직 바인딩이 없음
+	if event.Rune != 0 && event.Mod != 0 {
+		return
+	}
+
+	if app.handleTextInputKey(event) {
+		return
+	}
+
+	switch app.appState.View().GetMode() {
+	case state.ViewModeJobs:
+		app.handleJobsKey(event)
+		return
+	case state.ViewModeTrash:
+		app.handleTrashKey(event)
+		return
+	case state.ViewModeOutput:
+		app.handleOutputKey(event)
+		return
+	}
+
 	if event.Rune != 0 {
 		app.handleRuneKey(event.Rune)
 		return
@@ -150,8 +392,13 @@ func (app *App) handleKeyPress(event terminal.KeyPressEvent) {
 		// 입력 모드 취소
 		viewState := app.appState.View()
 		if viewState.IsWaitingForInput() {
-			viewState.SetInputMode(state.InputModeNormal)
-			viewState.ClearPrompt()
+			app.endInput()
+			return
+		}
+
+		// 적용 중인 필터가 있으면 먼저 해제
+		if event.Key == terminal.KeyEsc && viewState.GetFilter() != "" {
+			viewState.ClearFilter()
 			return
 		}
 
@@ -160,10 +407,19 @@ func (app *App) handleKeyPress(event terminal.KeyPressEvent) {
 		app.moveDown()
 	case terminal.KeyArrowUp:
 		app.moveUp()
-	case terminal.KeyArrowRight, terminal.KeyEnter:
+	case terminal.KeyEnter:
+		if app.pickMode && app.pick() {
+			return
+		}
+		app.expandOrEnter()
+	case terminal.KeyArrowRight:
 		app.expandOrEnter()
 	case terminal.KeyArrowLeft:
 		app.collapseOrParent()
+	case terminal.KeyCtrlL:
+		app.refreshAll()
+	case terminal.KeyCtrlR:
+		app.redo()
 	}
 }
 
@@ -182,6 +438,12 @@ func (app *App) handleRuneKey(r rune) {
 		viewState.SetInputMode(state.InputModeNormal)
 		viewState.ClearPrompt()
 		return
+	case state.InputModeConfirmDelete:
+		app.handleConfirmDeleteKey(r)
+		return
+	case state.InputModeResolveConflict:
+		app.handleConflictKey(r)
+		return
 	}
 
 	// 일반 키 처리
@@ -204,12 +466,146 @@ func (app *App) handleRuneKey(r rune) {
 	case '\'':
 		viewState.SetInputMode(state.InputModeWaitingForJump)
 		viewState.SetPrompt(" Jump to: _")
+	case 'v':
+		app.layout.TogglePreview()
+	case 's':
+		viewState.CycleSortType()
+		app.applySort()
+	case 'S':
+		viewState.ToggleSortReverse()
+		app.applySort()
+	case '.':
+		viewState.ToggleHidden()
+		app.ensureCursorVisible()
+	case 'R':
+		app.refreshCurrent()
+	case 'r':
+		app.bulkRename()
+	case 'y':
+		app.yank(false)
+	case 'x':
+		app.yank(true)
+	case 'p':
+		app.paste()
+	case 'd':
+		app.confirmDelete(deleteToTrash)
+	case 'D':
+		app.confirmDelete(deletePermanently)
+	case 'T':
+		app.openTrash()
+	case 'c':
+		app.startChmod()
+	case 'J':
+		app.toggleJobsView()
+	case 'X':
+		app.cancelRunningJobs()
+	case 'u':
+		app.undo()
+	case ':':
+		app.startCommand(false)
+	case '!':
+		app.startCommand(true)
+	case 'o':
+		app.toggleOutputView()
+	case 'I':
+		viewState.ToggleIgnored()
+		app.ensureCursorVisible()
+		app.startIndex()
+	case 'f':
+		viewState.SetInputMode(state.InputModeFilter)
+		viewState.SetInputText(viewState.GetFilter())
+		app.updateFilter()
+	case '/':
+		app.startSearch()
+	case 'n':
+		app.cycleSearchResult(1)
+	case 'N':
+		app.cycleSearchResult(-1)
+	}
+}
+
+// 커서 노드가 숨겨졌으면 보이는 가장 가까운 조상으로 이동
+func (app *App) ensureCursorVisible() {
+	visible := app.walker.GetVisibleNodes(app.appState.View().VisibleOptions())
+	node := app.appState.Cursor().GetCurrentNode()
+
+	for node != nil && !slices.Contains(visible, node) {
+		node = node.Parent
+	}
+
+	if node != nil {
+		app.appState.Cursor().SetCurrentNode(node)
+	}
+}
+
+// 다중 선택이 있으면 선택된 경로를, 없으면 커서 위치의 파일을 고르고 종료
+func (app *App) pick() bool {
+	selected := app.appState.Selection().GetSelectedNodes()
+	if len(selected) > 0 {
+		paths := make([]string, 0, len(selected))
+		for _, node := range selected {
+			paths = append(paths, node.Path)
+		}
+
+		app.picked = paths
+		app.running = false
+		return true
+	}
+
+	currentNode := app.appState.Cursor().GetCurrentNode()
+	if currentNode == nil || currentNode.IsDir {
+		return false
+	}
+
+	app.picked = []string{currentNode.Path}
+	app.running = false
+	return true
+}
+
+func (app *App) locate(path string) error {
+	node, err := app.filetree.Reveal(path)
+	app.pruneDetached()
+	if err != nil {
+		return err
 	}
+
+	app.showNode(node)
+	app.appState.Cursor().SetCurrentNode(node)
+	return nil
+}
+
+// 노드나 조상이 숨김 파일이거나 무시된 항목이면 보이도록 표시 옵션을 켜고 알림
+func (app *App) showNode(node *filetree.TreeNode) {
+	viewState := app.appState.View()
+
+	var shown []string
+	for ; node != nil && node.Parent != nil; node = node.Parent {
+		if node.IsHidden() && !viewState.ShowHidden() {
+			viewState.ToggleHidden()
+			shown = append(shown, "hidden")
+		}
+		if node.Ignored && !viewState.ShowIgnored() {
+			viewState.ToggleIgnored()
+			app.startIndex()
+			shown = append(shown, "ignored")
+		}
+	}
+
+	if len(shown) > 0 {
+		viewState.SetMessage(fmt.Sprintf("Showing %s files", strings.Join(shown, " and ")))
+	}
+}
+
+// 바뀐 정렬 옵션으로 로드된 트리를 다시 정렬
+func (app *App) applySort() {
+	opts := app.appState.View().SortOptions()
+	app.filetree.SetSortOptions(opts)
+	app.trash.tree.SetSortOptions(opts)
 }
 
 func (app *App) moveDown() {
 	currentNode := app.appState.Cursor().GetCurrentNode()
-	nextNode := app.walker.GetNextVisibleNode(currentNode)
+	nextNode := app.activeWalker().GetNextVisibleNode(currentNode, app.appState.View().VisibleOptions())
 
 	if nextNode != nil {
 		app.appState.Cursor().SetCurrentNode(nextNode)
@@ -218,7 +614,7 @@ func (app *App) moveDown() {
 
 func (app *App) moveUp() {
 	currentNode := app.appState.Cursor().GetCurrentNode()
-	prevNode := app.walker.GetPrevVisibleNode(currentNode)
+	prevNode := app.activeWalker().GetPrevVisibleNode(currentNode, app.appState.View().VisibleOptions())
 
 	if prevNode != nil {
 		app.appState.Cursor().SetCurrentNode(prevNode)
@@ -228,8 +624,14 @@ func (app *App) moveUp() {
 func (app *App) expandOrEnter() {
 	currentNode := app.appState.Cursor().GetCurrentNode()
 
-	if currentNode != nil && currentNode.IsDir {
-		app.filetree.ExpandNode(currentNode)
+	if currentNode == nil {
+		return
+	}
+
+	if currentNode.IsDir {
+		app.expandNode(currentNode)
+	} else if app.appState.View().GetMode() == state.ViewModeNormal {
+		app.openFile(currentNode)
 	}
 }
 
@@ -241,7 +643,7 @@ func (app *App) collapseOrParent() {
 	}
 
 	if currentNode.IsDir && currentNode.Expanded {
-		app.filetree.CollapseNode(currentNode)
+		app.activeTree().CollapseNode(currentNode)
 	} else if currentNode.Parent != nil {
 		app.appState.Cursor().SetCurrentNode(currentNode.Parent)
 	}
@@ -256,7 +658,7 @@ func (app *App) toggleSelection() {
 
 func (app *App) adjustScroll(screenHeight int) {
 	currentNode := app.appState.Cursor().GetCurrentNode()
-	visibleNodes := app.walker.GetVisibleNodes()
+	visibleNodes := app.activeWalker().GetVisibleNodes(app.appState.View().VisibleOptions())
 
 	currentIndex := -1
 	for i, node := range visibleNodes {
@@ -298,22 +700,13 @@ func (app *App) jumpToBookmark(mark string) {
 }
 
 func (app *App) handleResize() {
-	newWidth, newHeight, err := app.term.GetSize()
-	if err != nil {
-		return
-	}
-
-	if newWidth == app.width && newHeight == app.height {
+	if !app.updateSize() {
 		return
 	}
 
-	app.width = newWidth
-	app.height = newHeight
-
-	app.layout.SetSize(app.width, app.height)
-
 	app.adjustScroll(app.height)
+	app.requestPreview()
 
-	app.term.ClearScreen()
-	app.layout.Render(app.term, app.appState)
+	app.clearScreen()
+	app.render()
 }
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"time"
+
+	"github.com/minimal1/twf-clone/internal/state"
+	"github.com/minimal1/twf-clone/internal/terminal"
+)
+
+const (
+	doubleClickInterval = 400 * time.Millisecond
+	wheelLines          = 3
+)
+
+func (app *App) handleMouse(event terminal.MouseEvent) {
+	viewState := app.appState.View()
+	if viewState.IsWaitingForInput() {
+		return
+	}
+
+	mode := viewState.GetMode()
+	if mode == state.ViewModeOutput {
+		switch event.Button {
+		case terminal.MouseWheelUp:
+			viewState.ScrollOutput(-wheelLines)
+		case terminal.MouseWheelDown:
+			viewState.ScrollOutput(wheelLines)
+		}
+		return
+	}
+	if mode != state.ViewModeNormal && mode != state.ViewModeTrash {
+		return
+	}
+
+	switch {
+	case app.layout.TreeRect().Contains(event.X, event.Y):
+		app.handleTreeMouse(event)
+	case app.layout.PreviewVisible() && app.layout.PreviewRect().Contains(event.X, event.Y):
+		switch event.Button {
+		case terminal.MouseWheelUp:
+			app.scrollPreview(-wheelLines)
+		case terminal.MouseWheelDown:
+			app.scrollPreview(wheelLines)
+		}
+	}
+}
+
+func (app *App) handleTreeMouse(event terminal.MouseEvent) {
+	switch event.Button {
+	case terminal.MouseWheelUp:
+		app.scrollTree(-wheelLines)
+		return
+	case terminal.MouseWheelDown:
+		app.scrollTree(wheelLines)
+		return
+	case terminal.MouseLeft:
+	default:
+		return
+	}
+	if event.Action == terminal.MouseRelease {
+		return
+	}
+
+	visibleNodes := app.activeWalker().GetVisibleNodes(app.appState.View().VisibleOptions())
+	index := app.appState.View().GetScrollOffset() + event.Y - app.layout.TreeRect().Y
+	if index < 0 || index >= len(visibleNodes) {
+		return
+	}
+
+	node := visibleNodes[index]
+	app.appState.Cursor().SetCurrentNode(node)
+
+	// 드래그는 커서만 옮김
+	if event.Action == terminal.MouseDrag {
+		return
+	}
+
+	now := time.Now()
+	if node == app.lastClickNode && now.Sub(app.lastClickTime) < doubleClickInterval {
+		// 세 번째 클릭이 다시 더블 클릭이 되지 않도록 초기화
+		app.lastClickNode = nil
+		if node.IsDir {
+			if node.Expanded {
+				app.activeTree().CollapseNode(node)
+			} else {
+				app.expandNode(node)
+			}
+		}
+		return
+	}
+	app.lastClickNode, app.lastClickTime = node, now
+}
+
+// 화면을 스크롤하고, 커서가 화면 밖으로 나가면 화면 안쪽 끝으로 옮김
+func (app *App) scrollTree(delta int) {
+	viewState := app.appState.View()
+	visibleNodes := app.activeWalker().GetVisibleNodes(viewState.VisibleOptions())
+	treeHeight := app.layout.TreeRect().Height
+
+	if delta < 0 {
+		viewState.ScrollUp(-delta)
+	} else {
+		viewState.ScrollDown(delta)
+	}
+	offset := min(viewState.GetScrollOffset(), max(len(visibleNodes)-treeHeight, 0))
+	viewState.SetScrollOffset(offset)
+	if len(visibleNodes) == 0 {
+		return
+	}
+
+	cursor := app.appState.Cursor()
+	currentIndex := 0
+	for i, node := range visibleNodes {
+		if node == cursor.GetCurrentNode() {
+			currentIndex = i
+			break
+		}
+	}
+
+	switch {
+	case currentIndex < offset:
+		cursor.SetCurrentNode(visibleNodes[offset])
+	case currentIndex >= offset+treeHeight:
+		cursor.SetCurrentNode(visibleNodes[min(offset+treeHeight, len(visibleNodes))-1])
+	}
+}
+
+// 읽어 둔 줄 끝까지 내렸으면 한 화면만큼 더 읽음
+func (app *App) scrollPreview(delta int) {
+	visible := app.layout.PreviewRect().Height - 1
+	atEnd := app.previewView.ScrollBy(delta, visible)
+
+	if atEnd && app.previewView.LineCount() >= visible+app.previewExtra {
+		app.previewExtra += visible
+		app.requestPreview()
+	}
+}
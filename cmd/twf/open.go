@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/minimal1/twf-clone/internal/filetree"
+	"github.com/minimal1/twf-clone/internal/preview"
+	"github.com/minimal1/twf-clone/internal/terminal"
+)
+
+// opener 규칙에 따라 파일을 엶. 분리 실행이 아니면 명령이 끝날 때까지 TUI 를 내려놓음
+func (app *App) openFile(node *filetree.TreeNode) {
+	rule, ok := app.appState.Config().FindOpener(node.Path, detectMIME(node.Path))
+	if !ok {
+		app.appState.View().SetMessage(fmt.Sprintf("No opener for %s", node.Name))
+		return
+	}
+
+	command := strings.ReplaceAll(rule.Command, "{}", preview.ShellQuote(node.Path))
+	if !strings.Contains(rule.Command, "{}") {
+		command += " " + preview.ShellQuote(node.Path)
+	}
+
+	if rule.Detach {
+		if err := detach(command); err != nil {
+			app.appState.View().SetMessage(fmt.Sprintf("Open: %v", err))
+		}
+		return
+	}
+
+	err := app.releaseTerminal(func(ctx context.Context) error {
+		return runInTerminal(ctx, command)
+	})
+	if err != nil {
+		app.appState.View().SetMessage(fmt.Sprintf("Open: %v", err))
+	}
+
+	// 편집기에서 파일을 저장했거나 만들었을 수 있음
+	app.refreshAll()
+}
+
+// 터미널을 넘겨주고 명령이 끝날 때까지 기다림
+func runInTerminal(ctx context.Context, command string) error {
+	tty, err := terminal.OpenTTY()
+	if err != nil {
+		return err
+	}
+	defer tty.Close()
+
+	cmd := terminalCommand(ctx, "sh", "-c", command)
+	cmd.Stdin = tty
+	cmd.Stdout = tty
+	cmd.Stderr = tty
+	return cmd.Run()
+}
+
+// 새 세션에서 실행해 twf 가 끝나도 계속 실행되게 함. 출력은 버림
+func detach(command string) error {
+	cmd := exec.Command("sh", "-c", command)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	go cmd.Wait()
+	return nil
+}
+
+// 파일 앞부분으로 MIME 타입을 추측하고, 알 수 없으면 확장자로 판단
+func detectMIME(path string) string {
+	mimeType := "application/octet-stream"
+
+	if file, err := os.Open(path); err == nil {
+		buf := make([]byte, 512)
+		n, _ := file.Read(buf)
+		file.Close()
+		mimeType = http.DetectContentType(buf[:n])
+	}
+
+	if mimeType == "application/octet-stream" {
+		if byExt := mime.TypeByExtension(filepath.Ext(path)); byExt != "" {
+			mimeType = byExt
+		}
+	}
+
+	mimeType, _, _ = strings.Cut(mimeType, ";")
+	return mimeType
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// 종료 코드
+const (
+	exitOK        = 0
+	exitError     = 1
+	exitUsage     = 2
+	exitCancelled = 130
+)
+
+type outputFormat int
+
+const (
+	outputNewline outputFormat = iota
+	outputNull
+	outputJSON
+)
+
+func writePaths(w io.Writer, paths []string, format outputFormat) error {
+	switch format {
+	case outputJSON:
+		return json.NewEncoder(w).Encode(paths)
+	case outputNull:
+		for _, path := range paths {
+			if _, err := fmt.Fprintf(w, "%s\x00", path); err != nil {
+				return err
+			}
+		}
+	default:
+		for _, path := range paths {
+			if _, err := fmt.Fprintln(w, path); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
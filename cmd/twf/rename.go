@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/minimal1/twf-clone/internal/fileops"
+	"github.com/minimal1/twf-clone/internal/filetree"
+	"github.com/minimal1/twf-clone/internal/state"
+	"github.com/minimal1/twf-clone/internal/terminal"
+)
+
+// 선택된 노드가 있으면 선택된 노드를, 없으면 커서 디렉토리의 보이는 하위 항목을 대상으로 함
+func (app *App) renameTargets() []*filetree.TreeNode {
+	if selected := app.appState.Selection().GetSelectedNodes(); len(selected) > 0 {
+		return app.operationTargets()
+	}
+
+	dir := app.appState.Cursor().GetCurrentNode()
+	if dir == nil {
+		return nil
+	}
+	if !dir.IsDir && dir.Parent != nil {
+		dir = dir.Parent
+	}
+	if !dir.Loaded {
+		if err := app.filetree.ExpandNode(dir); err != nil {
+			app.appState.View().SetMessage(err.Error())
+			return nil
+		}
+	}
+
+	opts := app.appState.View().VisibleOptions()
+	var targets []*filetree.TreeNode
+	for _, child := range dir.Children {
+		if !opts.ShowHidden && child.IsHidden() {
+			continue
+		}
+		if !opts.ShowIgnored && child.Ignored {
+			continue
+		}
+		targets = append(targets, child)
+	}
+	return targets
+}
+
+// 대상 이름을 한 줄에 하나씩 $EDITOR 로 열고, 고친 결과를 확인받은 뒤 한꺼번에 이름을 바꿈
+func (app *App) bulkRename() {
+	targets := app.renameTargets()
+	if len(targets) == 0 {
+		return
+	}
+
+	olds := make([]string, len(targets))
+	for i, node := range targets {
+		olds[i] = node.Path
+	}
+
+	file, err := os.CreateTemp("", "twf-rename-*.txt")
+	if err != nil {
+		app.appState.View().SetMessage(err.Error())
+		return
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.WriteString(fileops.FormatRenames(olds))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		app.appState.View().SetMessage(err.Error())
+		return
+	}
+
+	var renames []fileops.Rename
+	confirmed := false
+	err = app.releaseTerminal(func(ctx context.Context) error {
+		if err := app.runEditor(ctx, file.Name()); err != nil {
+			return err
+		}
+
+		edited, err := os.ReadFile(file.Name())
+		if err != nil {
+			return err
+		}
+		renames, err = fileops.ParseRenames(olds, string(edited))
+		if err != nil || len(renames) == 0 {
+			return err
+		}
+
+		confirmed = app.confirmRenames(renames)
+		return nil
+	})
+	if err != nil {
+		app.appState.View().SetMessage(fmt.Sprintf("Rename: %v", err))
+		return
+	}
+	if len(renames) == 0 {
+		app.appState.View().SetMessage("Nothing to rename")
+		return
+	}
+	if !confirmed {
+		app.appState.View().SetMessage("Rename cancelled")
+		return
+	}
+
+	err = fileops.ApplyRenames(renames)
+	if err == nil {
+		app.appState.History().Record(renameOperation(renames))
+		app.appState.Selection().ClearSelection()
+		app.appState.View().SetMessage(fmt.Sprintf("Renamed %d item(s)", len(renames)))
+	} else {
+		app.appState.View().SetMessage(fmt.Sprintf("Rename: %v", err))
+	}
+	app.refreshAll()
+}
+
+// $VISUAL, $EDITOR, vi 순서로 편집기를 찾아 터미널에서 실행
+func (app *App) runEditor(ctx context.Context, path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	tty, err := terminal.OpenTTY()
+	if err != nil {
+		return err
+	}
+	defer tty.Close()
+
+	// 편집기 설정에 인자가 들어 있을 수 있으므로 셸로 실행
+	cmd := terminalCommand(ctx, "sh", "-c", editor+` "$1"`, "sh", path)
+	cmd.Stdin = tty
+	cmd.Stdout = tty
+	cmd.Stderr = tty
+	return cmd.Run()
+}
+
+// 바뀔 이름을 보여 주고 적용할지 물음. 터미널은 일반 모드인 상태
+func (app *App) confirmRenames(renames []fileops.Rename) bool {
+	profile := app.term.ColorProfile()
+	removed := terminal.Style{Fg: terminal.ColorRed}.Sequence(profile)
+	added := terminal.Style{Fg: terminal.ColorGreen}.Sequence(profile)
+
+	var b strings.Builder
+	for _, rename := range renames {
+		b.WriteString(removed + "- " + rename.From + terminal.SGRReset + "\n")
+		b.WriteString(added + "+ " + rename.To + terminal.SGRReset + "\n")
+	}
+	fmt.Fprintf(&b, "Apply %d rename(s)? [y/N] ", len(renames))
+	app.term.Write([]byte(b.String()))
+
+	buf := make([]byte, 64)
+	n, err := app.term.Read(buf)
+	if err != nil {
+		return false
+	}
+	answer := strings.TrimSpace(string(buf[:n]))
+	return answer == "y" || answer == "Y"
+}
+
+func renameOperation(renames []fileops.Rename) state.Operation {
+	op := state.Operation{Kind: state.OperationRename, Timestamp: time.Now()}
+	for _, rename := range renames {
+		op.Changes = append(op.Changes, state.FileChange{From: rename.From, To: rename.To})
+	}
+	return op
+}
+
+// 기록된 이름 변경을 한꺼번에 적용. 되돌릴 때는 방향을 바꿈
+func applyRenameOperation(op state.Operation, action historyAction) error {
+	renames := make([]fileops.Rename, len(op.Changes))
+	for i, change := range op.Changes {
+		renames[i] = fileops.Rename{From: change.From, To: change.To}
+		if action == historyUndo {
+			renames[i] = fileops.Rename{From: change.To, To: change.From}
+		}
+	}
+	return fileops.ApplyRenames(renames)
+}
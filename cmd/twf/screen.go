@@ -0,0 +1,123 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/minimal1/twf-clone/internal/terminal"
+)
+
+// -height 가 지정되면 대체 화면 대신 프롬프트 아래 영역에 그림
+func (app *App) enterScreen() {
+	app.updateSize()
+
+	if app.inline {
+		// 그릴 영역만큼 줄을 확보 (화면 맨 아래에서는 스크롤됨)
+		app.term.Write([]byte(strings.Repeat("\r\n", app.height-1)))
+	} else {
+		app.term.EnterAltScreen()
+	}
+
+	app.enableModes()
+	app.term.Write([]byte(terminal.SyncOutputQuery))
+	app.clearScreen()
+}
+
+func (app *App) enableModes() {
+	app.term.HideCursor()
+	app.term.EnableMouse()
+	app.term.EnableBracketedPaste()
+	app.term.EnableFocusReporting()
+}
+
+// SIGCONT 로 다시 실행되면 셸이 바꿔 놓았을 수 있는 터미널 상태를 되돌리고 전부 다시 그림
+func (app *App) resumeScreen() {
+	// Ctrl-Z 로 직접 멈췄으면 suspend 에서 이미 되돌렸음
+	if app.suspended {
+		app.suspended = false
+		return
+	}
+
+	app.term.EnableRawMode()
+	if !app.inline {
+		app.term.EnterAltScreen()
+	}
+	app.enableModes()
+
+	app.updateSize()
+	app.clearScreen()
+}
+
+func (app *App) exitScreen() {
+	app.term.DisableMouse()
+	app.term.DisableBracketedPaste()
+	app.term.DisableFocusReporting()
+
+	if app.inline {
+		app.clearScreen()
+		app.term.MoveCursorTo(app.top, 1)
+	} else {
+		app.term.ExitAltScreen()
+	}
+
+	app.term.ShowCursor()
+}
+
+// 화면을 지우고 다음 render 에서 전부 다시 그리게 함
+func (app *App) clearScreen() {
+	app.screen.Invalidate()
+
+	if !app.inline {
+		app.term.ClearScreen()
+		return
+	}
+
+	for row := app.top; row < app.top+app.height; row++ {
+		app.term.MoveCursorTo(row, 1)
+		app.term.ClearLine()
+	}
+}
+
+// 터미널 크기를 다시 읽어 그릴 영역을 계산. 바뀌었으면 true
+func (app *App) updateSize() bool {
+	termWidth, termHeight, err := app.term.GetSize()
+	if err != nil {
+		return false
+	}
+
+	height, top := termHeight, 1
+	if rows := app.appState.Config().GetHeight().Rows(termHeight); rows > 0 {
+		height, top = rows, termHeight-rows+1
+	}
+
+	changed := termWidth != app.width || height != app.height || top != app.top
+	app.width, app.height, app.top = termWidth, height, top
+	app.inline = height != termHeight
+
+	app.screen.Resize(app.width, app.height)
+	app.screen.SetOrigin(app.top)
+	if app.layout != nil {
+		app.layout.SetSize(app.width, app.height)
+		app.layout.SetOrigin(app.top)
+	}
+
+	return changed
+}
+
+// 화면 버퍼에 새로 그린 뒤 이전 프레임과 달라진 칸만 내보냄
+func (app *App) render() error {
+	app.syncViews()
+	app.screen.Clear()
+	if err := app.layout.Render(app.screen, app.appState); err != nil {
+		return err
+	}
+	return app.screen.Flush()
+}
+
+// state 에 두지 않는 검색 결과와 작업 목록을 그리기 전에 뷰로 넘김
+func (app *App) syncViews() {
+	app.searchView.SetResults(app.ranked.Results, app.searchIndex)
+	app.previewView.SetSearchResult(app.selectedSearchResult())
+	app.jobsView.SetJobs(app.jobList, app.jobIndex)
+	app.statusView.SetSearchPosition(app.searchIndex, len(app.ranked.Results))
+	app.statusView.SetRunningJob(app.runningJob())
+}
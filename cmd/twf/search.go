@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/minimal1/twf-clone/internal/search"
+	"github.com/minimal1/twf-clone/internal/state"
+)
+
+const indexRefreshInterval = 150 * time.Millisecond
+
+// 파일이 계속 바뀌는 동안 인덱싱을 쉬지 않고 반복하지 않도록 다시 인덱싱하는 최소 간격
+const reindexInterval = 5 * time.Second
+
+// 루트 전체를 백그라운드에서 인덱싱. 진행 상황은 indexTick 마다 반영
+func (app *App) startIndex() {
+	if app.index != nil {
+		app.index.Cancel()
+	}
+
+	opts := search.IndexOptions{
+		Root:           app.filetree.GetRoot().Path,
+		FollowSymlinks: app.appState.Config().GetFollowSymlinks(),
+	}
+	if !app.appState.View().ShowIgnored() {
+		opts.Ignore = app.ignoreMatcher.Ignored
+	}
+
+	app.index = search.StartIndex(context.Background(), opts)
+	app.indexStale = false
+	app.indexStarted = time.Now()
+	// 이전 인덱스로 계산 중이던 결과는 버림
+	app.ranker.Cancel()
+	app.rankPending = false
+
+	if app.indexTicker != nil {
+		app.indexTicker.Stop()
+	}
+	app.indexTicker = time.NewTicker(indexRefreshInterval)
+	app.appState.View().SetIndexProgress(0, true)
+}
+
+func (app *App) stopIndex() {
+	if app.index != nil {
+		app.index.Cancel()
+	}
+	if app.indexTicker != nil {
+		app.indexTicker.Stop()
+		app.indexTicker = nil
+	}
+}
+
+func (app *App) indexTick() <-chan time.Time {
+	if app.indexTicker == nil {
+		return nil
+	}
+	return app.indexTicker.C
+}
+
+func (app *App) handleIndexTick() {
+	files, _, done := app.index.Progress()
+	app.appState.View().SetIndexProgress(files, !done)
+
+	if app.appState.View().GetMode() == state.ViewModeSearch {
+		app.extendSearch()
+	}
+
+	if !done {
+		return
+	}
+
+	// 검색 중에는 결과가 비었다 다시 채워지지 않도록 검색을 마친 뒤에 다시 인덱싱
+	if app.indexStale {
+		if app.appState.View().GetMode() != state.ViewModeSearch && time.Since(app.indexStarted) >= reindexInterval {
+			app.startIndex()
+		}
+		return
+	}
+
+	app.indexTicker.Stop()
+	app.indexTicker = nil
+}
+
+// 인덱스를 다시 만들어야 함을 표시. 진행 중인 인덱싱이 끝난 뒤 틱에서 다시 시작
+func (app *App) markIndexStale() {
+	app.indexStale = true
+	if app.indexTicker == nil {
+		app.indexTicker = time.NewTicker(indexRefreshInterval)
+	}
+}
+
+// 로드 여부와 관계없이 트리 전체 경로를 대상으로 퍼지 검색
+func (app *App) startSearch() {
+	if app.indexStale {
+		app.startIndex()
+	}
+
+	viewState := app.appState.View()
+	viewState.EnterSearchMode()
+	viewState.SetInputMode(state.InputModeSearch)
+	viewState.SetInputText("")
+	app.ranked = search.Ranking{}
+	app.searchIndex = 0
+
+	app.updateSearch()
+}
+
+// 순위는 ranker 에서 계산하고 결과는 handleRanking 에서 반영
+func (app *App) updateSearch() {
+	viewState := app.appState.View()
+	query := viewState.GetInputText()
+
+	viewState.UpdateSearchQuery(query)
+	if query == "" {
+		app.ranker.Cancel()
+		app.rankPending = false
+		app.ranked = search.Ranking{}
+		app.searchIndex = 0
+	} else {
+		app.ranker.Request(app.index, query, viewState.ShowHidden())
+		app.rankPending = true
+	}
+
+	app.updateSearchPrompt()
+}
+
+// 인덱싱 중 새로 찾은 경로만 순위를 매겨 지금 결과에 합침. 계산 중이면 끝난 뒤의 틱에서 따라잡음
+func (app *App) extendSearch() {
+	viewState := app.appState.View()
+	query := viewState.GetSearchQuery()
+	if query == "" || app.rankPending {
+		return
+	}
+
+	switch {
+	case app.ranked.Query != query || app.ranked.Index != app.index:
+		app.ranker.Request(app.index, query, viewState.ShowHidden())
+	case len(app.index.Snapshot()) > app.ranked.Count:
+		app.ranker.Extend(app.ranked, viewState.ShowHidden())
+	default:
+		return
+	}
+	app.rankPending = true
+}
+
+// 검색어가 그대로면 (인덱싱 중 새로 찾은 경로를 반영할 때) 선택한 결과를 유지
+func (app *App) handleRanking(ranking search.Ranking) {
+	viewState := app.appState.View()
+	if viewState.GetMode() != state.ViewModeSearch || ranking.Query != viewState.GetSearchQuery() || ranking.Index != app.index {
+		return
+	}
+	app.rankPending = false
+
+	var selectedPath string
+	if selected := app.selectedSearchResult(); selected != nil && ranking.Query == app.ranked.Query {
+		selectedPath = selected.Path
+	}
+
+	app.ranked = ranking
+	app.searchIndex = 0
+	for i, result := range ranking.Results {
+		if result.Path == selectedPath {
+			app.searchIndex = i
+			break
+		}
+	}
+	app.updateSearchPrompt()
+
+	// 인덱싱이 끝난 뒤에는 틱이 없으므로 마지막으로 찾은 경로를 바로 반영
+	if _, _, done := app.index.Progress(); done {
+		app.extendSearch()
+	}
+}
+
+func (app *App) updateSearchPrompt() {
+	viewState := app.appState.View()
+	results := app.ranked.Results
+
+	position := 0
+	if len(results) > 0 {
+		position = app.searchIndex + 1
+	}
+
+	viewState.SetPrompt(fmt.Sprintf(" /%s_  [%d/%d]", viewState.GetInputText(), position, len(results)))
+}
+
+// Enter: 선택한 결과를 트리에서 펼쳐 보여주고 n/N 을 위해 결과는 유지
+func (app *App) confirmSearch() {
+	viewState := app.appState.View()
+	result := app.selectedSearchResult()
+
+	app.ranker.Cancel()
+	app.rankPending = false
+	app.endInput()
+	viewState.ExitSearchMode()
+
+	if result != nil {
+		app.revealSearchResult(result)
+	}
+}
+
+func (app *App) cancelSearch() {
+	viewState := app.appState.View()
+
+	app.ranker.Cancel()
+	app.rankPending = false
+	app.endInput()
+	viewState.ExitSearchMode()
+	viewState.ClearSearch()
+	app.ranked = search.Ranking{}
+	app.searchIndex = 0
+}
+
+func (app *App) selectedSearchResult() *search.Result {
+	if app.searchIndex < 0 || app.searchIndex >= len(app.ranked.Results) {
+		return nil
+	}
+	return &app.ranked.Results[app.searchIndex]
+}
+
+// 결과 목록의 끝에서 처음으로 (또는 그 반대로) 넘어감
+func (app *App) moveSearchIndex(step int) {
+	count := len(app.ranked.Results)
+	if count == 0 {
+		return
+	}
+	app.searchIndex = (app.searchIndex + step + count) % count
+}
+
+func (app *App) cycleSearchResult(step int) {
+	if len(app.ranked.Results) == 0 {
+		return
+	}
+
+	app.moveSearchIndex(step)
+	app.revealSearchResult(app.selectedSearchResult())
+}
+
+func (app *App) revealSearchResult(result *search.Result) {
+	if err := app.locate(result.Path); err != nil {
+		app.appState.View().SetMessage(err.Error())
+		return
+	}
+
+	// 필터 때문에 결과가 가려지지 않도록
+	app.appState.View().ClearFilter()
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+
+	"github.com/minimal1/twf-clone/internal/terminal"
+)
+
+// 종료 시그널을 전달한 외부 프로그램이 끝나기를 기다리는 시간. 지나면 강제로 종료
+const childStopDelay = 3 * time.Second
+
+// 편집기 같은 외부 프로그램이 터미널을 쓰는 동안 TUI 를 내려놓음.
+// 이벤트 읽기를 멈추고 raw 모드와 대체 화면을 해제한 뒤 fn 이 끝나면 되돌림.
+// 그 사이 SIGTERM, SIGHUP 을 받으면 ctx 를 취소하고 fn 이 끝난 뒤 twf 를 종료
+func (app *App) releaseTerminal(fn func(ctx context.Context) error) error {
+	app.term.PauseEvents()
+
+	app.exitScreen()
+	app.term.DisableRawMode()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// 읽기 고루틴은 멈췄으므로 이 동안 오는 이벤트는 시그널뿐
+	stop := make(chan struct{})
+	watched := make(chan struct{})
+	interrupted, resumed := false, false
+	go func() {
+		defer close(watched)
+		for {
+			select {
+			case event := <-app.term.Events():
+				switch event.(type) {
+				case terminal.InterruptEvent:
+					interrupted = true
+					cancel()
+					// 확인 질문에 대한 응답을 기다리는 Read 를 깨움
+					app.term.SetReadDeadline(time.Now())
+				case terminal.ResumeEvent:
+					resumed = true
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	err := fn(ctx)
+
+	close(stop)
+	<-watched
+	app.term.SetReadDeadline(time.Time{})
+
+	// 화면은 아래에서 되돌리므로 suspend 가 기다리던 SIGCONT 는 여기서 받은 것으로 처리
+	if resumed {
+		app.suspended = false
+	}
+	if interrupted {
+		app.running = false
+	}
+
+	app.term.EnableRawMode()
+	app.enterScreen()
+	app.term.ResumeEvents()
+
+	return err
+}
+
+// 터미널을 넘겨받아 실행할 외부 프로그램. ctx 가 취소되면 SIGTERM 을 전달
+func terminalCommand(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(syscall.SIGTERM)
+	}
+	cmd.WaitDelay = childStopDelay
+	return cmd
+}
+
+// Ctrl-Z: 터미널을 원래대로 돌려놓고 SIGTSTP 로 멈춤. fg 로 돌아오면 화면을 다시 그림
+func (app *App) suspend() {
+	app.releaseTerminal(func(ctx context.Context) error {
+		// twf 만 멈춤. 프로세스 그룹에 보내면 twf 를 실행한 스크립트 같은 다른 프로세스까지 멈춤
+		app.suspended = true
+		err := syscall.Kill(os.Getpid(), syscall.SIGTSTP)
+		if err != nil {
+			app.suspended = false
+		}
+		return err
+	})
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/minimal1/twf-clone/internal/filetree"
+	"github.com/minimal1/twf-clone/internal/jobs"
+	"github.com/minimal1/twf-clone/internal/state"
+	"github.com/minimal1/twf-clone/internal/terminal"
+	"github.com/minimal1/twf-clone/internal/trash"
+)
+
+// 휴지통 보기에서 사용하는 트리. 루트는 실제 디렉토리가 아니고 각 항목이 자식
+type trashBrowser struct {
+	tree   *filetree.FileTreeImpl
+	walker *filetree.Walker
+	items  map[*filetree.TreeNode]trash.Item
+
+	// 휴지통 보기에 들어가기 전 트리의 커서와 스크롤
+	savedCursor *filetree.TreeNode
+	savedScroll int
+}
+
+func newTrashBrowser() *trashBrowser {
+	tree := filetree.NewFileTree()
+	return &trashBrowser{
+		tree:   tree,
+		walker: filetree.NewWalker(tree),
+		items:  make(map[*filetree.TreeNode]trash.Item),
+	}
+}
+
+// 지금 커서가 움직이는 트리. 휴지통 보기에서는 휴지통 트리
+func (app *App) activeTree() *filetree.FileTreeImpl {
+	if app.appState.View().GetMode() == state.ViewModeTrash {
+		return app.trash.tree
+	}
+	return app.filetree
+}
+
+func (app *App) activeWalker() *filetree.Walker {
+	if app.appState.View().GetMode() == state.ViewModeTrash {
+		return app.trash.walker
+	}
+	return app.walker
+}
+
+func (app *App) openTrash() {
+	viewState := app.appState.View()
+	app.trash.savedCursor = app.appState.Cursor().GetCurrentNode()
+	app.trash.savedScroll = viewState.GetScrollOffset()
+
+	viewState.SetMode(state.ViewModeTrash)
+	viewState.SetScrollOffset(0)
+	app.reloadTrash()
+}
+
+func (app *App) closeTrash() {
+	viewState := app.appState.View()
+	viewState.SetMode(state.ViewModeNormal)
+	viewState.SetScrollOffset(app.trash.savedScroll)
+
+	app.appState.Cursor().SetCurrentNode(app.trash.savedCursor)
+	app.refreshAll()
+}
+
+// 휴지통 목록을 다시 읽어 트리를 만듦
+func (app *App) reloadTrash() {
+	items, err := trash.List()
+	if err != nil {
+		app.appState.View().SetMessage(err.Error())
+	}
+
+	root := &filetree.TreeNode{Name: "Trash", IsDir: true, Loaded: true, Expanded: true}
+	app.trash.items = make(map[*filetree.TreeNode]trash.Item)
+	for _, item := range items {
+		node, err := filetree.NewTreeNode(item.Path)
+		if err != nil {
+			continue
+		}
+		node.Name = fmt.Sprintf("%s  (%s)", item.OriginalPath, item.DeletedAt.Format("2006-01-02 15:04"))
+		root.AddChild(node)
+		app.trash.items[node] = item
+	}
+	app.trash.tree.SetRoot(root)
+	app.trash.tree.SetSortOptions(app.appState.View().SortOptions())
+
+	cursor := root
+	if len(root.Children) > 0 {
+		cursor = root.Children[0]
+	}
+	app.appState.Cursor().SetCurrentNode(cursor)
+	app.appState.View().SetScrollOffset(0)
+}
+
+// 커서가 가리키는 휴지통 항목. 항목 안의 하위 노드면 그 항목
+func (app *App) trashItemAtCursor() (trash.Item, bool) {
+	for node := app.appState.Cursor().GetCurrentNode(); node != nil; node = node.Parent {
+		if item, ok := app.trash.items[node]; ok {
+			return item, true
+		}
+	}
+	return trash.Item{}, false
+}
+
+func (app *App) handleTrashKey(event terminal.KeyPressEvent) {
+	if app.appState.View().GetInputMode() == state.InputModeConfirmDelete {
+		app.handleConfirmDeleteKey(event.Rune)
+		return
+	}
+
+	switch {
+	case event.Rune == 'j' || event.Key == terminal.KeyArrowDown:
+		app.moveDown()
+	case event.Rune == 'k' || event.Key == terminal.KeyArrowUp:
+		app.moveUp()
+	case event.Rune == 'l' || event.Key == terminal.KeyArrowRight:
+		app.expandOrEnter()
+	case event.Rune == 'h' || event.Key == terminal.KeyArrowLeft:
+		app.collapseOrParent()
+	case event.Rune == 'r':
+		app.restoreTrashItem()
+	case event.Rune == 'D':
+		app.confirmDelete(deleteFromTrash)
+	case event.Rune == 'T' || event.Rune == 'q' || event.Key == terminal.KeyEsc || event.Key == terminal.KeyCtrlC:
+		app.closeTrash()
+	}
+}
+
+func (app *App) restoreTrashItem() {
+	item, ok := app.trashItemAtCursor()
+	if !ok {
+		return
+	}
+
+	if err := trash.PrepareRestore(item); err != nil {
+		app.appState.View().SetMessage(err.Error())
+		return
+	}
+
+	job := app.startJob(jobs.Spec{Kind: jobs.KindMove, Tasks: []jobs.Task{{Src: item.Path, Dst: item.OriginalPath}}})
+	app.restoreJobs[job.ID] = item
+}
+
+// 되돌린 항목의 .trashinfo 를 지우고 휴지통 목록을 다시 읽음
+func (app *App) finishRestore(job *jobs.Job) {
+	item, ok := app.restoreJobs[job.ID]
+	if !ok {
+		return
+	}
+	delete(app.restoreJobs, job.ID)
+
+	if len(job.Completed()) == 0 {
+		return
+	}
+	trash.Forget(item.Path)
+	if app.appState.View().GetMode() == state.ViewModeTrash {
+		app.reloadTrash()
+	}
+}
+
+// 휴지통에서 영구 삭제. 정보 파일을 먼저 지우고 내용은 백그라운드에서 지움
+func (app *App) purgeTrashItem() {
+	item, ok := app.trashItemAtCursor()
+	if !ok {
+		return
+	}
+
+	if err := trash.Forget(item.Path); err != nil {
+		app.appState.View().SetMessage(err.Error())
+		return
+	}
+
+	app.startJob(jobs.Spec{Kind: jobs.KindDelete, Tasks: []jobs.Task{{Src: item.Path}}})
+	app.reloadTrash()
+}
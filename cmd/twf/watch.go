@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/minimal1/twf-clone/internal/filetree"
+	"github.com/minimal1/twf-clone/internal/ignore"
+	"github.com/minimal1/twf-clone/internal/state"
+)
+
+// 연달아 오는 파일 시스템 이벤트를 모아 한 번만 다시 그림
+const watchDebounce = 50 * time.Millisecond
+
+func (app *App) watchEvents() <-chan []filetree.WatchEvent {
+	if app.watcher == nil {
+		return nil
+	}
+	return app.watcher.Events()
+}
+
+// 감시 이벤트를 트리에 합치고 커서와 미리보기를 맞춤
+func (app *App) handleWatchEvents(events []filetree.WatchEvent) {
+	var ignoreDirs []string
+	stalePreview := false
+	staleIndex := false
+
+	for _, event := range events {
+		// 내용만 바뀐 경우가 아니면 검색 인덱스의 경로 목록이 달라짐
+		if event.Op != filetree.WatchChange {
+			staleIndex = true
+		}
+		if event.Op == filetree.WatchOverflow {
+			stalePreview = true
+			continue
+		}
+		if ignore.IsIgnoreFile(event.Name) {
+			ignoreDirs = append(ignoreDirs, event.Dir)
+		}
+
+		// 미리보기 중인 파일이나 디렉토리가 바뀌었으면 다시 요청
+		path := filepath.Join(event.Dir, event.Name)
+		if strings.HasPrefix(app.previewKey, path+":") || strings.HasPrefix(app.previewKey, event.Dir+":") {
+			stalePreview = true
+		}
+	}
+
+	app.filetree.ApplyEvents(events)
+
+	for _, dir := range ignoreDirs {
+		app.ignoreMatcher.Invalidate(dir)
+		app.filetree.UpdateIgnored(app.filetree.NodeAt(dir))
+	}
+
+	app.pruneDetached()
+
+	if stalePreview {
+		app.previewKey = ""
+	}
+	if staleIndex {
+		app.markIndexStale()
+	}
+}
+
+// 커서가 있는 디렉토리 (파일이면 상위 디렉토리)를 다시 읽음
+func (app *App) refreshCurrent() {
+	node := app.appState.Cursor().GetCurrentNode()
+	if node == nil {
+		return
+	}
+	if (!node.IsDir || !node.Loaded) && node.Parent != nil {
+		node = node.Parent
+	}
+
+	err := app.filetree.RefreshNode(node)
+	if errors.Is(err, fs.ErrNotExist) && node.Parent != nil {
+		// 디렉토리 자체가 사라졌으면 상위 디렉토리에서 정리
+		err = app.filetree.RefreshNode(node.Parent)
+	}
+	if err != nil {
+		app.appState.View().SetMessage(err.Error())
+	}
+	app.pruneDetached()
+}
+
+// 펼쳐진 디렉토리를 모두 다시 읽음
+func (app *App) refreshAll() {
+	if err := app.filetree.RefreshNode(app.filetree.GetRoot()); err != nil {
+		app.appState.View().SetMessage(err.Error())
+	}
+	app.pruneDetached()
+	app.previewKey = ""
+}
+
+// 접혀 있던 디렉토리를 펼치면 다시 읽으면서 사라진 항목이 트리에서 빠질 수 있음
+func (app *App) expandNode(node *filetree.TreeNode) {
+	app.activeTree().ExpandNode(node)
+	app.pruneDetached()
+}
+
+// 트리에서 빠진 노드를 커서, 선택, 마크, 클립보드에서 정리
+func (app *App) pruneDetached() {
+	// 휴지통 보기에서는 커서가 휴지통 트리에 있으므로 닫을 때 정리
+	if app.appState.View().GetMode() == state.ViewModeTrash {
+		return
+	}
+
+	app.appState.Prune(app.filetree.Contains)
+	app.ensureCursorVisible()
+}
@@ -2,9 +2,12 @@ module github.com/minimal1/twf-clone
 
 go 1.24.4
 
+require (
+	golang.org/x/sys v0.36.0
+	golang.org/x/term v0.35.0
+)
+
 require (
 	go.uber.org/multierr v1.10.0 // indirect
 	go.uber.org/zap v1.27.0 // indirect
-	golang.org/x/sys v0.36.0 // indirect
-	golang.org/x/term v0.35.0 // indirect
 )
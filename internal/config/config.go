@@ -0,0 +1,192 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/minimal1/twf-clone/internal/state"
+)
+
+// 설정 파일 탐색 순서 (확장자별)
+var fileNames = []string{"config.toml", "config.yaml", "config.yml", "config.json"}
+
+// 적용 우선순위: 기본값 < 설정 파일 < TWF_* 환경 변수 < 명령행 플래그
+type Loader struct {
+	flags *flag.FlagSet
+}
+
+// 모든 설정 키를 플래그로 등록. 기본값은 ConfigState 쪽에서 관리하므로
+// 실제로 지정된 플래그만 ApplyFlags 에서 반영
+func NewLoader(flags *flag.FlagSet) *Loader {
+	defaults := state.NewConfigState()
+
+	for _, k := range keys {
+		if k.isBool {
+			flags.Bool(flagName(k.name), k.get(defaults) == "true", k.usage)
+		} else if k.isList {
+			flags.Var(&listFlag{}, flagName(k.name), k.usage)
+		} else {
+			flags.String(flagName(k.name), k.get(defaults), k.usage)
+		}
+	}
+
+	return &Loader{flags: flags}
+}
+
+func (l *Loader) Load(cs *state.ConfigState, configPath string) error {
+	if configPath == "" {
+		configPath = os.Getenv("TWF_CONFIG")
+	}
+	if configPath == "" {
+		configPath = FindFile()
+	}
+
+	if configPath != "" {
+		if err := LoadFile(cs, configPath); err != nil {
+			return err
+		}
+	}
+
+	if err := LoadEnv(cs, os.Environ()); err != nil {
+		return err
+	}
+
+	return l.ApplyFlags(cs)
+}
+
+func (l *Loader) ApplyFlags(cs *state.ConfigState) error {
+	var firstErr error
+
+	l.flags.Visit(func(f *flag.Flag) {
+		if firstErr != nil {
+			return
+		}
+
+		name := strings.ReplaceAll(f.Name, "-", "_")
+		k, err := lookupKey(name)
+		if err != nil {
+			return // 설정 키가 아닌 플래그 (-pick 등)
+		}
+
+		var value any = f.Value.String()
+		if list, ok := f.Value.(*listFlag); ok {
+			value = []string(*list)
+		}
+		if err := k.set(cs, value); err != nil {
+			firstErr = fmt.Errorf("flag -%s: %w", f.Name, err)
+		}
+	})
+
+	return firstErr
+}
+
+// 지정할 때마다 항목을 하나씩 더하는 플래그
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *listFlag) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func Dir() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "twf")
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "twf")
+}
+
+func FindFile() string {
+	dir := Dir()
+	if dir == "" {
+		return ""
+	}
+
+	for _, name := range fileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+
+	return ""
+}
+
+func LoadFile(cs *state.ConfigState, path string) error {
+	entries, err := readEntries(path, "config")
+	if err != nil {
+		return err
+	}
+
+	for _, e := range entries {
+		if err := apply(cs, e.key, e.value); err != nil {
+			if e.line > 0 {
+				return fmt.Errorf("config file %s: line %d: %w", path, e.line, err)
+			}
+			return fmt.Errorf("config file %s: %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// 확장자에 맞는 형식으로 파일을 읽음. kind 는 오류 메시지용 (config, theme)
+func readEntries(path, kind string) ([]entry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%s file %s does not exist", kind, path)
+		}
+		return nil, err
+	}
+
+	var entries []entry
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		entries, err = parseTOML(data)
+	case ".yaml", ".yml":
+		entries, err = parseYAML(data)
+	case ".json":
+		entries, err = parseJSON(data)
+	default:
+		return nil, fmt.Errorf("%s file %s: unsupported format (want .toml, .yaml or .json)", kind, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s file %s: %w", kind, path, err)
+	}
+
+	return entries, nil
+}
+
+func LoadEnv(cs *state.ConfigState, environ []string) error {
+	for _, kv := range environ {
+		name, value, ok := strings.Cut(kv, "=")
+		if !ok || !strings.HasPrefix(name, "TWF_") || name == "TWF_CONFIG" {
+			continue
+		}
+
+		// 다른 도구가 쓰는 TWF_ 변수일 수 있으므로 모르는 이름은 건너뜀
+		key := strings.ToLower(strings.TrimPrefix(name, "TWF_"))
+		if _, err := lookupKey(key); err != nil {
+			continue
+		}
+		if err := apply(cs, key, value); err != nil {
+			return fmt.Errorf("environment variable %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,261 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/minimal1/twf-clone/internal/state"
+)
+
+type setter func(cs *state.ConfigState, value any) error
+
+type key struct {
+	name   string
+	usage  string
+	isBool bool
+	isList bool // 플래그를 여러 번 지정해 항목을 더함
+	get    func(cs *state.ConfigState) string
+	set    setter
+}
+
+// 설정 파일 키, TWF_* 환경 변수, 명령행 플래그가 모두 이 목록을 공유
+var keys = []key{
+	{name: "dir", get: func(cs *state.ConfigState) string { return cs.GetDefaultPath() }, usage: "root `directory` to browse", set: stringSetter(func(cs *state.ConfigState, v string) error {
+		cs.SetDefaultPath(v)
+		return nil
+	})},
+	{name: "hidden", get: func(cs *state.ConfigState) string { return strconv.FormatBool(cs.GetShowHidden()) }, usage: "show hidden files", isBool: true, set: boolSetter((*state.ConfigState).SetShowHidden)},
+	{name: "show_ignored", get: func(cs *state.ConfigState) string { return strconv.FormatBool(cs.GetShowIgnored()) }, usage: "show entries matched by .gitignore/.ignore/.twfignore (dimmed)", isBool: true, set: boolSetter((*state.ConfigState).SetShowIgnored)},
+	{name: "ignore", get: func(cs *state.ConfigState) string { return strings.Join(cs.GetIgnoreGlobs(), ",") }, usage: "extra `glob` to ignore (gitignore syntax); repeat for more", isList: true, set: func(cs *state.ConfigState, value any) error {
+		globs, err := toStringList(value)
+		if err != nil {
+			return err
+		}
+		cs.SetIgnoreGlobs(globs)
+		return nil
+	}},
+	{name: "sort", get: func(cs *state.ConfigState) string { return cs.GetSortType().String() }, usage: "sort `order`: name, size, date, type or natural", set: stringSetter(func(cs *state.ConfigState, v string) error {
+		sortType, err := state.ParseSortType(v)
+		if err != nil {
+			return err
+		}
+		cs.SetSortType(sortType)
+		return nil
+	})},
+	{name: "reverse", get: func(cs *state.ConfigState) string { return strconv.FormatBool(cs.GetSortReverse()) }, usage: "reverse the sort order", isBool: true, set: boolSetter((*state.ConfigState).SetSortReverse)},
+	{name: "dirs_first", get: func(cs *state.ConfigState) string { return strconv.FormatBool(cs.GetDirsFirst()) }, usage: "list directories before files", isBool: true, set: boolSetter((*state.ConfigState).SetDirsFirst)},
+	{name: "ignore_case", get: func(cs *state.ConfigState) string { return strconv.FormatBool(cs.GetIgnoreCase()) }, usage: "sort names case-insensitively", isBool: true, set: boolSetter((*state.ConfigState).SetIgnoreCase)},
+	{name: "theme", get: func(cs *state.ConfigState) string { return cs.GetColorScheme() }, usage: "color `theme`: dark, light, solarized, high-contrast or a theme file", set: stringSetter(func(cs *state.ConfigState, v string) error {
+		if v == "" {
+			return fmt.Errorf("theme cannot be empty")
+		}
+		t, err := LoadTheme(v)
+		if err != nil {
+			return err
+		}
+		cs.SetColorScheme(v)
+		cs.SetTheme(t)
+		return nil
+	})},
+	{name: "follow_symlinks", get: func(cs *state.ConfigState) string { return strconv.FormatBool(cs.GetFollowSymlinks()) }, usage: "follow symbolic links to directories", isBool: true, set: boolSetter((*state.ConfigState).SetFollowSymlinks)},
+	{name: "preview", get: func(cs *state.ConfigState) string { return cs.GetPreviewCommand() }, usage: "preview `command` for the node under the cursor ({} is replaced by its path)", set: stringSetter(func(cs *state.ConfigState, v string) error {
+		cs.SetPreviewCommand(v)
+		return nil
+	})},
+	{name: "height", get: func(cs *state.ConfigState) string { return cs.GetHeight().String() }, usage: "draw below the prompt using `lines` rows (or a percentage like 40%) instead of the full screen", set: stringSetter(func(cs *state.ConfigState, v string) error {
+		height, err := state.ParseHeight(v)
+		if err != nil {
+			return err
+		}
+		cs.SetHeight(height)
+		return nil
+	})},
+	{name: "line_numbers", get: func(cs *state.ConfigState) string { return strconv.FormatBool(cs.GetShowLineNumbers()) }, usage: "show line numbers in the text preview", isBool: true, set: boolSetter((*state.ConfigState).SetShowLineNumbers)},
+	{name: "confirm_delete", get: func(cs *state.ConfigState) string { return strconv.FormatBool(cs.GetConfirmDelete()) }, usage: "ask before deleting files", isBool: true, set: boolSetter((*state.ConfigState).SetConfirmDelete)},
+	{name: "opener", get: func(cs *state.ConfigState) string {
+		rules := make([]string, len(cs.GetOpeners()))
+		for i, rule := range cs.GetOpeners() {
+			rules[i] = rule.String()
+		}
+		return strings.Join(rules, ",")
+	}, usage: "`rule` like text/*=vim {} or .pdf=zathura {} & for opening files (& detaches); repeat for more", isList: true, set: func(cs *state.ConfigState, value any) error {
+		list, err := toStringList(value)
+		if err != nil {
+			return err
+		}
+		rules := make([]state.OpenRule, len(list))
+		for i, item := range list {
+			if rules[i], err = state.ParseOpenRule(item); err != nil {
+				return err
+			}
+		}
+		cs.SetOpeners(rules)
+		return nil
+	}},
+	{name: "max_history", get: func(cs *state.ConfigState) string { return strconv.Itoa(cs.GetMaxHistory()) }, usage: "maximum number of history `entries`", set: func(cs *state.ConfigState, value any) error {
+		n, err := toInt(value)
+		if err != nil {
+			return err
+		}
+		if n <= 0 {
+			return fmt.Errorf("max_history must be positive, got %d", n)
+		}
+		cs.SetMaxHistory(n)
+		return nil
+	}},
+}
+
+func apply(cs *state.ConfigState, name string, value any) error {
+	k, err := lookupKey(name)
+	if err != nil {
+		return err
+	}
+
+	if err := k.set(cs, value); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+func lookupKey(name string) (key, error) {
+	for _, k := range keys {
+		if k.name == name {
+			return k, nil
+		}
+	}
+
+	if suggestion := closestKey(name); suggestion != "" {
+		return key{}, fmt.Errorf("unknown key %q (did you mean %q?)", name, suggestion)
+	}
+	return key{}, fmt.Errorf("unknown key %q", name)
+}
+
+func flagName(name string) string {
+	return strings.ReplaceAll(name, "_", "-")
+}
+
+func stringSetter(fn func(cs *state.ConfigState, v string) error) setter {
+	return func(cs *state.ConfigState, value any) error {
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected a string, got %v", value)
+		}
+		return fn(cs, s)
+	}
+}
+
+func boolSetter(fn func(cs *state.ConfigState, v bool)) setter {
+	return func(cs *state.ConfigState, value any) error {
+		b, err := toBool(value)
+		if err != nil {
+			return err
+		}
+		fn(cs, b)
+		return nil
+	}
+}
+
+func toBool(value any) (bool, error) {
+	switch v := value.(type) {
+	case bool:
+		return v, nil
+	case string:
+		switch strings.ToLower(v) {
+		case "1", "true", "yes", "on":
+			return true, nil
+		case "0", "false", "no", "off":
+			return false, nil
+		}
+	}
+
+	return false, fmt.Errorf("expected a boolean, got %v", value)
+}
+
+// 배열 또는 쉼표로 구분한 문자열. 반복한 플래그는 []string
+func toStringList(value any) ([]string, error) {
+	var list []string
+
+	switch v := value.(type) {
+	case []string:
+		list = v
+	case string:
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	case []any:
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected a list of strings, got %v", item)
+			}
+			list = append(list, s)
+		}
+	default:
+		return nil, fmt.Errorf("expected a list of strings, got %v", value)
+	}
+
+	return list, nil
+}
+
+func toInt(value any) (int, error) {
+	switch v := value.(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case float64:
+		if v == float64(int(v)) {
+			return int(v), nil
+		}
+	case string:
+		if n, err := strconv.Atoi(v); err == nil {
+			return n, nil
+		}
+	}
+
+	return 0, fmt.Errorf("expected an integer, got %v", value)
+}
+
+// 오타로 보이는 키에 대해 편집 거리가 가장 가까운 키를 제안
+func closestKey(name string) string {
+	candidates := make([]string, 0, len(keys))
+	for _, k := range keys {
+		candidates = append(candidates, k.name)
+	}
+	sort.Strings(candidates)
+
+	best, bestDistance := "", 3
+	for _, candidate := range candidates {
+		if d := editDistance(strings.ReplaceAll(name, "-", "_"), candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+
+	return best
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr := make([]int, len(b)+1)
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev = curr
+	}
+
+	return prev[len(b)]
+}
@@ -0,0 +1,252 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type entry struct {
+	key   string
+	value any
+	line  int
+}
+
+// TOML 의 평탄한 부분집합: key = value, 문자열/불리언/숫자/한 줄 배열
+func parseTOML(data []byte) ([]entry, error) {
+	var entries []entry
+	seen := make(map[string]int)
+
+	for i, raw := range strings.Split(string(data), "\n") {
+		lineNo := i + 1
+		line := strings.TrimSpace(stripComment(raw))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			return nil, fmt.Errorf("line %d: tables are not supported, write top-level key = value lines only: %s", lineNo, line)
+		}
+
+		name, rawValue, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value, got %q", lineNo, line)
+		}
+
+		name = strings.TrimSpace(name)
+		if !strings.HasPrefix(name, `"`) && !strings.HasPrefix(name, "'") && strings.Contains(name, ".") {
+			return nil, fmt.Errorf("line %d: dotted keys are not supported, write top-level key = value lines only: %s", lineNo, name)
+		}
+		name = strings.Trim(name, `"'`)
+		if prev, dup := seen[name]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q (first set on line %d)", lineNo, name, prev)
+		}
+		seen[name] = lineNo
+
+		value, err := parseTOMLValue(strings.TrimSpace(rawValue))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %w", lineNo, name, err)
+		}
+
+		entries = append(entries, entry{key: name, value: value, line: lineNo})
+	}
+
+	return entries, nil
+}
+
+func parseTOMLValue(s string) (any, error) {
+	switch {
+	case s == "":
+		return nil, fmt.Errorf("missing value")
+	case s == "true" || s == "false":
+		return s == "true", nil
+	case strings.HasPrefix(s, `"`) || strings.HasPrefix(s, "'"):
+		return parseQuoted(s)
+	case strings.HasPrefix(s, "["):
+		if !strings.HasSuffix(s, "]") {
+			return nil, fmt.Errorf("unterminated array (arrays must fit on one line)")
+		}
+		return parseList(s[1:len(s)-1], parseTOMLValue)
+	}
+
+	if n, err := strconv.ParseInt(strings.ReplaceAll(s, "_", ""), 10, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f, nil
+	}
+
+	return nil, fmt.Errorf("invalid value %s (strings must be quoted)", s)
+}
+
+// YAML 의 평탄한 부분집합: key: value 와 "- item" 목록
+func parseYAML(data []byte) ([]entry, error) {
+	var entries []entry
+	seen := make(map[string]int)
+	var list *entry
+
+	for i, raw := range strings.Split(string(data), "\n") {
+		lineNo := i + 1
+		trimmed := strings.TrimSpace(stripComment(raw))
+		if trimmed == "" || trimmed == "---" {
+			continue
+		}
+
+		indented := raw[0] == ' ' || raw[0] == '\t'
+		isItem := strings.HasPrefix(trimmed, "- ") || trimmed == "-"
+		if indented || isItem {
+			if list == nil || !isItem {
+				return nil, fmt.Errorf("line %d: nested mappings are not supported, write top-level key: value lines and - item lists only", lineNo)
+			}
+
+			item, err := parseYAMLScalar(strings.TrimSpace(strings.TrimPrefix(trimmed, "-")))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+
+			items, _ := list.value.([]any)
+			list.value = append(items, item)
+			continue
+		}
+
+		name, rawValue, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key: value, got %q", lineNo, trimmed)
+		}
+
+		name = strings.Trim(strings.TrimSpace(name), `"'`)
+		if prev, dup := seen[name]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q (first set on line %d)", lineNo, name, prev)
+		}
+		seen[name] = lineNo
+
+		rawValue = strings.TrimSpace(rawValue)
+		entries = append(entries, entry{key: name, line: lineNo})
+		list = nil
+
+		// 값이 비어 있으면 다음 줄부터 목록
+		if rawValue == "" {
+			list = &entries[len(entries)-1]
+			list.value = []any{}
+			continue
+		}
+
+		value, err := parseYAMLScalar(rawValue)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %s: %w", lineNo, name, err)
+		}
+		entries[len(entries)-1].value = value
+	}
+
+	return entries, nil
+}
+
+func parseYAMLScalar(s string) (any, error) {
+	switch {
+	case strings.HasPrefix(s, `"`) || strings.HasPrefix(s, "'"):
+		return parseQuoted(s)
+	case strings.HasPrefix(s, "["):
+		if !strings.HasSuffix(s, "]") {
+			return nil, fmt.Errorf("unterminated list")
+		}
+		return parseList(s[1:len(s)-1], parseYAMLScalar)
+	case s == "true" || s == "false":
+		return s == "true", nil
+	}
+
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, nil
+	}
+
+	return s, nil
+}
+
+func parseJSON(data []byte) ([]entry, error) {
+	var values map[string]any
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	entries := make([]entry, 0, len(names))
+	for _, name := range names {
+		if _, nested := values[name].(map[string]any); nested {
+			return nil, fmt.Errorf("%s: nested objects are not supported, use top-level keys only", name)
+		}
+		entries = append(entries, entry{key: name, value: values[name]})
+	}
+
+	return entries, nil
+}
+
+func parseQuoted(s string) (string, error) {
+	if len(s) < 2 || s[len(s)-1] != s[0] {
+		return "", fmt.Errorf("unterminated string %s", s)
+	}
+
+	// 작은따옴표는 이스케이프 없이 그대로
+	if s[0] == '\'' {
+		return s[1 : len(s)-1], nil
+	}
+
+	unquoted, err := strconv.Unquote(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid string %s", s)
+	}
+	return unquoted, nil
+}
+
+func parseList(s string, parseItem func(string) (any, error)) ([]any, error) {
+	items := []any{}
+
+	for _, part := range splitOutsideQuotes(s, ',') {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		item, err := parseItem(part)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func stripComment(line string) string {
+	return splitOutsideQuotes(line, '#')[0]
+}
+
+func splitOutsideQuotes(s string, sep byte) []string {
+	var parts []string
+	var quote byte
+	start := 0
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(parts, s[start:])
+}
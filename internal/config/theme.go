@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/minimal1/twf-clone/internal/theme"
+)
+
+// 내장 테마 이름, 테마 파일 경로, 또는 설정 디렉토리의 themes/<이름>.toml (.yaml, .json)
+func LoadTheme(name string) (*theme.Theme, error) {
+	if t, ok := theme.Builtin(name); ok {
+		return t, nil
+	}
+
+	if strings.ContainsRune(name, os.PathSeparator) || filepath.Ext(name) != "" {
+		return loadThemeFile(name)
+	}
+
+	if dir := Dir(); dir != "" {
+		for _, fileName := range fileNames {
+			path := filepath.Join(dir, "themes", name+filepath.Ext(fileName))
+			if _, err := os.Stat(path); err == nil {
+				return loadThemeFile(path)
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("unknown theme %q (want one of %s or a theme file)", name, strings.Join(theme.Names(), ", "))
+}
+
+// base 키로 고른 내장 테마 (없으면 dark) 위에 요소별 스타일을 덮어씀
+func loadThemeFile(path string) (*theme.Theme, error) {
+	entries, err := readEntries(path, "theme")
+	if err != nil {
+		return nil, err
+	}
+
+	t := theme.Default()
+	for _, e := range entries {
+		if e.key != "base" {
+			continue
+		}
+		base, ok := e.value.(string)
+		if !ok {
+			return nil, themeError(path, e, fmt.Errorf("base: expected a string, got %v", e.value))
+		}
+		if t, ok = theme.Builtin(base); !ok {
+			return nil, themeError(path, e, fmt.Errorf("base: unknown theme %q (want one of %s)", base, strings.Join(theme.Names(), ", ")))
+		}
+	}
+
+	for _, e := range entries {
+		if e.key == "base" {
+			continue
+		}
+		spec, ok := e.value.(string)
+		if !ok {
+			return nil, themeError(path, e, fmt.Errorf("%s: expected a style string, got %v", e.key, e.value))
+		}
+		if err := t.Set(e.key, spec); err != nil {
+			return nil, themeError(path, e, err)
+		}
+	}
+
+	return t, nil
+}
+
+func themeError(path string, e entry, err error) error {
+	if e.line > 0 {
+		return fmt.Errorf("theme file %s: line %d: %w", path, e.line, err)
+	}
+	return fmt.Errorf("theme file %s: %w", path, err)
+}
@@ -0,0 +1,440 @@
+package fileops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// 대상 경로에 같은 이름이 있을 때의 처리
+type Conflict int
+
+const (
+	ConflictSkip Conflict = iota
+	ConflictOverwrite
+	ConflictRename
+)
+
+// 붙여넣기 한 건. Exists 면 충돌 처리 방법을 정해야 함
+type Item struct {
+	Src    string
+	Dst    string
+	Exists bool
+}
+
+// srcs 를 dir 안으로 옮기거나 복사할 계획을 세움
+func Plan(srcs []string, dir string) []Item {
+	items := make([]Item, 0, len(srcs))
+	for _, src := range srcs {
+		dst := filepath.Join(dir, filepath.Base(src))
+		_, err := os.Lstat(dst)
+		items = append(items, Item{Src: src, Dst: dst, Exists: err == nil})
+	}
+	return items
+}
+
+// 같은 이름이 없을 때까지 "name_1.ext" 처럼 번호를 붙인 경로
+func UniqueName(path string) string {
+	dir := filepath.Dir(path)
+	base := filepath.Base(path)
+	ext := filepath.Ext(base)
+	if ext == base {
+		ext = ""
+	}
+	name := strings.TrimSuffix(base, ext)
+
+	for i := 1; ; i++ {
+		candidate := filepath.Join(dir, fmt.Sprintf("%s_%d%s", name, i, ext))
+		if _, err := os.Lstat(candidate); errors.Is(err, os.ErrNotExist) {
+			return candidate
+		}
+	}
+}
+
+// 충돌 처리 방법에 따라 실제로 쓸 대상 경로를 정함. 건너뛰면 빈 문자열
+func Resolve(item Item, conflict Conflict) string {
+	if !item.Exists {
+		return item.Dst
+	}
+
+	switch conflict {
+	case ConflictOverwrite:
+		if item.Src == item.Dst {
+			return ""
+		}
+		return item.Dst
+	case ConflictRename:
+		return UniqueName(item.Dst)
+	default:
+		return ""
+	}
+}
+
+// 작업 진행 상황을 받는 쪽
+type Reporter interface {
+	AddFiles(n int64)
+	AddBytes(n int64)
+}
+
+type nopReporter struct{}
+
+func (nopReporter) AddFiles(int64) {}
+func (nopReporter) AddBytes(int64) {}
+
+func reporterOrNop(r Reporter) Reporter {
+	if r == nil {
+		return nopReporter{}
+	}
+	return r
+}
+
+// src 를 dst 로 재귀 복사. 권한과 수정 시각을 유지하고 dst 가 있으면 덮어씀.
+// 임시 이름으로 복사한 뒤 바꾸므로 취소되거나 실패하면 dst 는 그대로 남음
+func Copy(ctx context.Context, src, dst string, r Reporter) error {
+	return CopyReplacing(ctx, src, dst, "", r)
+}
+
+// Copy 와 같되 새 항목이 준비된 뒤 덮어쓸 dst 를 버리지 않고 backup 으로 옮김
+func CopyReplacing(ctx context.Context, src, dst, backup string, r Reporter) error {
+	if err := checkInside(src, dst); err != nil {
+		return err
+	}
+	if err := checkOverwrite(src, dst); err != nil {
+		return err
+	}
+
+	tmp := tempName(dst)
+	if err := copyPath(ctx, src, tmp, reporterOrNop(r)); err != nil {
+		removeTemp(tmp)
+		return err
+	}
+
+	if err := replace(tmp, dst, backup); err != nil {
+		removeTemp(tmp)
+		return err
+	}
+	return nil
+}
+
+// src 를 dst 로 이동. 다른 파일 시스템이면 복사 후 원본 삭제.
+// dst 가 있으면 새 항목을 옆에 옮겨 둔 뒤 바꾸므로 실패해도 기존 dst 는 남음
+func Move(ctx context.Context, src, dst string, r Reporter) error {
+	return MoveReplacing(ctx, src, dst, "", r)
+}
+
+// Move 와 같되 새 항목이 준비된 뒤 덮어쓸 dst 를 버리지 않고 backup 으로 옮김
+func MoveReplacing(ctx context.Context, src, dst, backup string, r Reporter) error {
+	r = reporterOrNop(r)
+	if src == dst {
+		return nil
+	}
+	if err := checkInside(src, dst); err != nil {
+		return err
+	}
+	if err := checkOverwrite(src, dst); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	files, bytes := Measure([]string{src})
+	tmp := tempName(dst)
+
+	err := os.Rename(src, tmp)
+	if errors.Is(err, syscall.EXDEV) {
+		if err := CopyReplacing(ctx, src, dst, backup, r); err != nil {
+			return err
+		}
+		return os.RemoveAll(src)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := replace(tmp, dst, backup); err != nil {
+		if rollback := os.Rename(tmp, src); rollback != nil {
+			return errors.Join(err, fmt.Errorf("restore %s: %w", src, rollback))
+		}
+		return err
+	}
+	r.AddFiles(files)
+	r.AddBytes(bytes)
+	return nil
+}
+
+// 하위 항목부터 지우며 진행 상황을 보고
+func Remove(ctx context.Context, path string, r Reporter) error {
+	r = reporterOrNop(r)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if err := Remove(ctx, filepath.Join(path, entry.Name()), r); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		r.AddFiles(1)
+		r.AddBytes(info.Size())
+	}
+	return nil
+}
+
+func Chmod(ctx context.Context, path string, mode os.FileMode, r Reporter) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		return err
+	}
+	reporterOrNop(r).AddFiles(1)
+	return nil
+}
+
+// paths 아래의 파일 수와 전체 크기. 심볼릭 링크는 따라가지 않음
+func Measure(paths []string) (files, bytes int64) {
+	for _, path := range paths {
+		filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return nil
+			}
+			files++
+			if info, err := entry.Info(); err == nil && info.Mode().IsRegular() {
+				bytes += info.Size()
+			}
+			return nil
+		})
+	}
+	return files, bytes
+}
+
+// path 아래 파일 크기의 합과 가장 최근 수정 시각. 복사본이 나중에 바뀌었는지 확인할 때 사용
+func Stamp(path string) (size int64, modTime time.Time, err error) {
+	err = filepath.WalkDir(path, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+		return nil
+	})
+	return size, modTime, err
+}
+
+// 같은 디렉토리의 숨김 임시 경로
+func tempName(path string) string {
+	return filepath.Join(filepath.Dir(path), fmt.Sprintf(".%s.twf-%d-%d", filepath.Base(path), os.Getpid(), time.Now().UnixNano()))
+}
+
+// 디렉토리를 자기 자신 안으로 복사하거나 옮기는 것을 막음
+func checkInside(src, dst string) error {
+	if isInside(src, dst) {
+		return fmt.Errorf("cannot copy %s into itself", filepath.Base(src))
+	}
+	return nil
+}
+
+// path 가 dir 의 하위 경로인지 확인
+func isInside(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// 덮어쓸 dst 가 src 를 품고 있으면 src 까지 사라지므로 막음
+func checkOverwrite(src, dst string) error {
+	if src != dst && isInside(dst, src) {
+		return fmt.Errorf("cannot overwrite %s with its own content", filepath.Base(dst))
+	}
+	return nil
+}
+
+// 준비해 둔 tmp 를 dst 자리에 놓음. 파일끼리는 rename 한 번으로 바꾸고,
+// 디렉토리가 끼어 있으면 기존 dst 를 비켜 둔 뒤 바꾸고 마지막에 지움.
+// backup 이 있으면 기존 dst 는 지우지 않고 그리로 옮김
+func replace(tmp, dst, backup string) error {
+	old, err := os.Lstat(dst)
+	if err != nil {
+		return os.Rename(tmp, dst)
+	}
+	if backup != "" {
+		return replaceKeeping(tmp, dst, backup)
+	}
+	info, err := os.Lstat(tmp)
+	if err != nil {
+		return err
+	}
+	if !old.IsDir() && !info.IsDir() {
+		return os.Rename(tmp, dst)
+	}
+
+	swap := tempName(dst)
+	if err := os.Rename(dst, swap); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		if rollback := os.Rename(swap, dst); rollback != nil {
+			return errors.Join(err, fmt.Errorf("restore %s: %w", dst, rollback))
+		}
+		return err
+	}
+	return removeTemp(swap)
+}
+
+// 기존 dst 를 backup 으로 옮긴 뒤 tmp 를 그 자리에 둠. 실패하면 dst 를 되돌림
+func replaceKeeping(tmp, dst, backup string) error {
+	if err := Move(context.Background(), dst, backup, nil); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		if rollback := Move(context.Background(), backup, dst, nil); rollback != nil {
+			return errors.Join(err, fmt.Errorf("restore %s: %w", dst, rollback))
+		}
+		return err
+	}
+	return nil
+}
+
+// 복사하면서 읽기 전용으로 맞춘 디렉토리도 지울 수 있도록 쓰기 권한을 되돌린 뒤 지움
+func removeTemp(path string) error {
+	filepath.WalkDir(path, func(p string, entry fs.DirEntry, err error) error {
+		if err == nil && entry.IsDir() {
+			os.Chmod(p, 0o700)
+		}
+		return nil
+	})
+	return os.RemoveAll(path)
+}
+
+func copyPath(ctx context.Context, src, dst string, r Reporter) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		target, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		if err := os.Symlink(target, dst); err != nil {
+			return err
+		}
+		r.AddFiles(1)
+		return nil
+	case info.IsDir():
+		return copyDir(ctx, src, dst, info, r)
+	case info.Mode().IsRegular():
+		return copyFile(ctx, src, dst, info, r)
+	default:
+		return fmt.Errorf("cannot copy special file %s", src)
+	}
+}
+
+func copyDir(ctx context.Context, src, dst string, info os.FileInfo, r Reporter) error {
+	// 읽기 전용 디렉토리도 채울 수 있도록 권한은 내용을 복사한 뒤에 맞춤
+	if err := os.Mkdir(dst, 0o700); err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := copyPath(ctx, filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name()), r); err != nil {
+			return err
+		}
+	}
+
+	if err := os.Chmod(dst, info.Mode().Perm()); err != nil {
+		return err
+	}
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
+
+func copyFile(ctx context.Context, src, dst string, info os.FileInfo, r Reporter) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, &progressReader{ctx: ctx, reader: in, reporter: r}); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+
+	if err := os.Chmod(dst, info.Mode().Perm()); err != nil {
+		return err
+	}
+	r.AddFiles(1)
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
+
+// 읽을 때마다 취소 여부를 확인하고 읽은 만큼 보고
+type progressReader struct {
+	ctx      context.Context
+	reader   io.Reader
+	reporter Reporter
+}
+
+func (pr *progressReader) Read(p []byte) (int, error) {
+	if err := pr.ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	n, err := pr.reader.Read(p)
+	pr.reporter.AddBytes(int64(n))
+	return n, err
+}
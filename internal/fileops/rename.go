@@ -0,0 +1,129 @@
+package fileops
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type Rename struct {
+	From string
+	To   string
+}
+
+// 편집기에 보여 줄 목록. 한 줄에 하나씩, 원래 항목이 있던 디렉토리 기준의 이름
+func FormatRenames(olds []string) string {
+	var b strings.Builder
+	for _, old := range olds {
+		b.WriteString(filepath.Base(old) + "\n")
+	}
+	return b.String()
+}
+
+// FormatRenames 로 만든 목록을 고친 결과를 원래 경로와 줄 단위로 맞춰 이름 변경 목록을 만듦.
+// 상대 경로는 원래 항목이 있던 디렉토리 기준
+func ParseRenames(olds []string, edited string) ([]Rename, error) {
+	lines := strings.Split(strings.TrimRight(edited, "\n"), "\n")
+	if edited == "" {
+		lines = nil
+	}
+	if len(lines) != len(olds) {
+		return nil, fmt.Errorf("expected %d lines, got %d", len(olds), len(lines))
+	}
+
+	var renames []Rename
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			return nil, fmt.Errorf("line %d is empty", i+1)
+		}
+		// 고치지 않은 줄
+		if line == filepath.Base(olds[i]) {
+			continue
+		}
+
+		to := line
+		if !filepath.IsAbs(to) {
+			to = filepath.Join(filepath.Dir(olds[i]), to)
+		}
+		to = filepath.Clean(to)
+
+		if to != filepath.Clean(olds[i]) {
+			renames = append(renames, Rename{From: olds[i], To: to})
+		}
+	}
+
+	return renames, CheckRenames(renames)
+}
+
+// 이름 변경을 적용할 수 있는지 확인. 대상이 겹치거나 이미 있으면 실패
+func CheckRenames(renames []Rename) error {
+	sources := make(map[string]bool, len(renames))
+	for _, rename := range renames {
+		sources[rename.From] = true
+	}
+
+	targets := make(map[string]bool, len(renames))
+	for _, rename := range renames {
+		if targets[rename.To] {
+			return fmt.Errorf("duplicate name %s", rename.To)
+		}
+		targets[rename.To] = true
+
+		if _, err := os.Lstat(rename.From); err != nil {
+			return fmt.Errorf("%s no longer exists", rename.From)
+		}
+		// 다른 항목이 비켜 줄 자리면 지금 있어도 괜찮음
+		if _, err := os.Lstat(rename.To); err == nil && !sources[rename.To] {
+			return fmt.Errorf("%s already exists", rename.To)
+		}
+		if info, err := os.Stat(filepath.Dir(rename.To)); err != nil || !info.IsDir() {
+			return fmt.Errorf("directory %s does not exist", filepath.Dir(rename.To))
+		}
+
+		if isInside(rename.From, rename.To) {
+			return fmt.Errorf("cannot move %s into itself", rename.From)
+		}
+		for other := range sources {
+			if isInside(other, rename.From) {
+				return fmt.Errorf("cannot rename %s together with its parent", rename.From)
+			}
+		}
+	}
+
+	return nil
+}
+
+// 모든 항목을 먼저 임시 이름으로 옮긴 뒤 새 이름으로 옮겨서
+// a→b, b→a 같은 교환이나 순환도 처리. 실패하면 가능한 만큼 되돌림
+func ApplyRenames(renames []Rename) error {
+	if err := CheckRenames(renames); err != nil {
+		return err
+	}
+
+	temps := make([]string, len(renames))
+	for i, rename := range renames {
+		temps[i] = tempName(rename.From)
+		if err := os.Rename(rename.From, temps[i]); err != nil {
+			for j := i - 1; j >= 0; j-- {
+				os.Rename(temps[j], renames[j].From)
+			}
+			return err
+		}
+	}
+
+	var errs []error
+	for i, rename := range renames {
+		if err := os.Rename(temps[i], rename.To); err != nil {
+			// 새 이름으로 옮기지 못한 항목은 원래 이름으로 되돌림
+			if restoreErr := os.Rename(temps[i], rename.From); restoreErr != nil {
+				err = fmt.Errorf("%w (left at %s)", err, temps[i])
+			}
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 type FileTree interface {
@@ -19,6 +20,12 @@ type FileTree interface {
 type FileTreeImpl struct {
 	root        *TreeNode
 	currentNode *TreeNode
+	sortOptions SortOptions
+	ignore      func(path string, isDir bool) bool
+	watcher     DirWatcher
+
+	// 디렉토리를 가리키는 심볼릭 링크를 디렉토리처럼 펼침
+	followSymlinks bool
 }
 
 func NewFileTree() *FileTreeImpl {
@@ -38,6 +45,21 @@ func (ft *FileTreeImpl) LoadRoot(path string) error {
 	return nil
 }
 
+// 실제 디렉토리가 아닌 노드를 루트로 사용 (휴지통 목록 등)
+func (ft *FileTreeImpl) SetRoot(node *TreeNode) {
+	ft.root = node
+	ft.currentNode = node
+}
+
+// 디렉토리를 읽을 때 각 항목의 Ignored 를 표시할 규칙
+func (ft *FileTreeImpl) SetIgnore(ignore func(path string, isDir bool) bool) {
+	ft.ignore = ignore
+}
+
+func (ft *FileTreeImpl) SetFollowSymlinks(follow bool) {
+	ft.followSymlinks = follow
+}
+
 func (ft *FileTreeImpl) GetRoot() *TreeNode {
 	return ft.root
 }
@@ -65,7 +87,10 @@ func (ft *FileTreeImpl) ExpandNode(node *TreeNode) error {
 	}
 
 	if node.Loaded {
+		// 접혀 있는 동안은 감시하지 않았으므로 다시 읽어 맞춤
 		node.Expanded = true
+		ft.watch(node)
+		ft.resyncExpanded(node)
 		return nil
 	}
 
@@ -73,6 +98,7 @@ func (ft *FileTreeImpl) ExpandNode(node *TreeNode) error {
 		return err
 	}
 	node.Expanded = true
+	ft.watch(node)
 
 	return nil
 }
@@ -90,31 +116,100 @@ func (ft *FileTreeImpl) loadChildren(node *TreeNode) error {
 			continue
 		}
 
-		child := NewTreeNodeFromInfo(childPath, info)
+		child := NewTreeNodeFromInfo(childPath, ft.followLink(childPath, info))
+		if ft.ignore != nil {
+			child.Ignored = ft.ignore(childPath, child.IsDir)
+		}
 		node.AddChild(child)
 	}
 
+	SortNodes(node.Children, ft.sortOptions)
 	node.Loaded = true
 	return nil
 }
 
+// 링크를 따라가도록 설정했으면 디렉토리를 가리키는 링크는 대상의 정보로 바꿈
+func (ft *FileTreeImpl) followLink(path string, info os.FileInfo) os.FileInfo {
+	if !ft.followSymlinks || info.Mode()&os.ModeSymlink == 0 {
+		return info
+	}
+
+	target, err := os.Stat(path)
+	if err != nil || !target.IsDir() {
+		return info
+	}
+	return target
+}
+
 func (ft *FileTreeImpl) CollapseNode(node *TreeNode) error {
 	if !node.CanExpand() {
 		return fmt.Errorf("failed to collapse node, caused by this node can't collapse")
 	}
 
 	node.Expanded = false
+	ft.unwatch(node)
 	return nil
 }
 
+// 디렉토리를 다시 읽어 기존 자식과 맞춤. 노드를 새로 만들지 않으므로
+// 펼침 상태와 노드를 가리키는 참조가 유지되고, 사라진 항목만 트리에서 빠짐
 func (ft *FileTreeImpl) RefreshNode(node *TreeNode) error {
-	if !node.IsDir {
+	if !node.IsDir || !node.Loaded {
 		return nil
 	}
 
-	node.Children = []*TreeNode{}
-	node.Loaded = false
-	node.Expanded = false
+	if _, err := ft.syncChildren(node); err != nil {
+		return err
+	}
+
+	for _, child := range node.Children {
+		if child.Expanded {
+			ft.resyncExpanded(child)
+		}
+	}
+
+	return nil
+}
+
+// 루트 아래의 path 까지 조상 디렉토리를 모두 펼치고 해당 노드를 반환
+func (ft *FileTreeImpl) Reveal(path string) (*TreeNode, error) {
+	if ft.root == nil {
+		return nil, fmt.Errorf("root is not loaded")
+	}
+
+	rootAbs, err := filepath.Abs(ft.root.Path)
+	if err != nil {
+		return nil, err
+	}
+	targetAbs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+
+	rel, err := filepath.Rel(rootAbs, targetAbs)
+	if err != nil {
+		return nil, err
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, fmt.Errorf("%s is outside of %s", path, ft.root.Path)
+	}
+
+	node := ft.root
+	if rel == "." {
+		return node, nil
+	}
+
+	for _, name := range strings.Split(rel, string(filepath.Separator)) {
+		if err := ft.ExpandNode(node); err != nil {
+			return nil, err
+		}
+
+		child := node.GetChildByName(name)
+		if child == nil {
+			return nil, fmt.Errorf("%s not found in %s", name, node.Path)
+		}
+		node = child
+	}
 
-	return ft.ExpandNode(node)
+	return node, nil
 }
@@ -3,6 +3,7 @@ package filetree
 import (
 	"fmt"
 	"os"
+	"strings"
 	"time"
 )
 
@@ -20,6 +21,8 @@ type TreeNode struct {
 	Loaded   bool
 
 	Selected bool
+	// .gitignore 등의 규칙에 걸린 노드
+	Ignored bool
 }
 
 func NewTreeNode(path string) (*TreeNode, error) {
@@ -94,6 +97,10 @@ func (n *TreeNode) Depth() int {
 	return depth
 }
 
+func (n *TreeNode) IsHidden() bool {
+	return !n.IsRoot() && strings.HasPrefix(n.Name, ".")
+}
+
 func (n *TreeNode) CanExpand() bool {
 	return n.IsDir
 }
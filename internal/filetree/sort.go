@@ -0,0 +1,152 @@
+package filetree
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+type SortKey int
+
+const (
+	SortKeyName SortKey = iota
+	SortKeySize
+	SortKeyDate
+	SortKeyType
+	SortKeyNatural
+)
+
+type SortOptions struct {
+	Key        SortKey
+	Reverse    bool
+	DirsFirst  bool
+	IgnoreCase bool
+}
+
+func (ft *FileTreeImpl) GetSortOptions() SortOptions {
+	return ft.sortOptions
+}
+
+// 정렬 기준을 바꾸고 이미 로드된 모든 디렉토리를 다시 정렬 (펼침 상태 유지)
+func (ft *FileTreeImpl) SetSortOptions(opts SortOptions) {
+	ft.sortOptions = opts
+
+	if ft.root != nil {
+		ft.sortRecursive(ft.root)
+	}
+}
+
+func (ft *FileTreeImpl) sortRecursive(node *TreeNode) {
+	if !node.Loaded {
+		return
+	}
+
+	SortNodes(node.Children, ft.sortOptions)
+	for _, child := range node.Children {
+		if child.IsDir {
+			ft.sortRecursive(child)
+		}
+	}
+}
+
+func SortNodes(nodes []*TreeNode, opts SortOptions) {
+	slices.SortStableFunc(nodes, func(a, b *TreeNode) int {
+		if opts.DirsFirst && a.IsDir != b.IsDir {
+			if a.IsDir {
+				return -1
+			}
+			return 1
+		}
+
+		result := compareNodes(a, b, opts)
+		if opts.Reverse {
+			return -result
+		}
+		return result
+	})
+}
+
+func compareNodes(a, b *TreeNode, opts SortOptions) int {
+	var result int
+
+	switch opts.Key {
+	case SortKeySize:
+		// 큰 파일 먼저
+		result = compareInt64(b.Size, a.Size)
+	case SortKeyDate:
+		// 최근 파일 먼저
+		result = b.ModTime.Compare(a.ModTime)
+	case SortKeyType:
+		result = strings.Compare(strings.ToLower(filepath.Ext(a.Name)), strings.ToLower(filepath.Ext(b.Name)))
+	case SortKeyNatural:
+		result = naturalCompare(foldName(a.Name, opts), foldName(b.Name, opts))
+	}
+
+	if result != 0 {
+		return result
+	}
+
+	if result = strings.Compare(foldName(a.Name, opts), foldName(b.Name, opts)); result != 0 {
+		return result
+	}
+	return strings.Compare(a.Name, b.Name)
+}
+
+func foldName(name string, opts SortOptions) string {
+	if opts.IgnoreCase {
+		return strings.ToLower(name)
+	}
+	return name
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// 숫자 구간은 값으로 비교 (file2 < file10)
+func naturalCompare(a, b string) int {
+	for a != "" && b != "" {
+		aDigits, bDigits := isDigit(a[0]), isDigit(b[0])
+
+		if aDigits && bDigits {
+			aNum, aRest := splitDigits(a)
+			bNum, bRest := splitDigits(b)
+
+			aTrim, bTrim := strings.TrimLeft(aNum, "0"), strings.TrimLeft(bNum, "0")
+			if len(aTrim) != len(bTrim) {
+				return compareInt64(int64(len(aTrim)), int64(len(bTrim)))
+			}
+			if result := strings.Compare(aTrim, bTrim); result != 0 {
+				return result
+			}
+
+			a, b = aRest, bRest
+			continue
+		}
+
+		if a[0] != b[0] {
+			return compareInt64(int64(a[0]), int64(b[0]))
+		}
+		a, b = a[1:], b[1:]
+	}
+
+	return compareInt64(int64(len(a)), int64(len(b)))
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func splitDigits(s string) (digits, rest string) {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
@@ -0,0 +1,334 @@
+package filetree
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 펼쳐진 디렉토리를 감시할 대상. nil 이면 감시하지 않음
+func (ft *FileTreeImpl) SetWatcher(watcher DirWatcher) {
+	ft.watcher = watcher
+}
+
+// 이미 로드된 노드 중에서 path 에 해당하는 노드를 찾음. 디렉토리를 새로 읽지는 않음
+func (ft *FileTreeImpl) NodeAt(path string) *TreeNode {
+	if ft.root == nil {
+		return nil
+	}
+
+	rel, err := filepath.Rel(ft.root.Path, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil
+	}
+
+	node := ft.root
+	if rel == "." {
+		return node
+	}
+
+	for _, name := range strings.Split(rel, string(filepath.Separator)) {
+		if !node.Loaded {
+			return nil
+		}
+
+		node = node.GetChildByName(name)
+		if node == nil {
+			return nil
+		}
+	}
+
+	return node
+}
+
+// 노드가 아직 트리에 붙어 있는지 확인
+func (ft *FileTreeImpl) Contains(node *TreeNode) bool {
+	return node != nil && ft.NodeAt(node.Path) == node
+}
+
+// 감시 이벤트를 트리에 반영하고 트리에서 빠진 노드를 반환.
+// 기존 노드는 그대로 재사용하므로 펼침/선택 상태와 노드를 가리키는 참조가 유지됨
+func (ft *FileTreeImpl) ApplyEvents(events []WatchEvent) []*TreeNode {
+	var removed []*TreeNode
+
+	for _, event := range events {
+		if event.Op == WatchOverflow {
+			return ft.resyncExpanded(ft.root)
+		}
+	}
+
+	// 같은 cookie 의 MoveFrom/MoveTo 는 이름 변경으로 묶음
+	moveTo := make(map[uint32]WatchEvent)
+	for _, event := range events {
+		if event.Op == WatchMoveTo && event.Cookie != 0 {
+			moveTo[event.Cookie] = event
+		}
+	}
+	paired := make(map[uint32]bool)
+
+	dirty := make(map[*TreeNode]bool)
+
+	for _, event := range events {
+		switch event.Op {
+		case WatchCreate:
+			ft.createChild(event.Dir, event.Name, dirty)
+		case WatchRemove:
+			removed = append(removed, ft.removeChild(event.Dir, event.Name)...)
+		case WatchChange:
+			ft.updateChild(event.Dir, event.Name, dirty)
+		case WatchMoveFrom:
+			to, ok := moveTo[event.Cookie]
+			if !ok {
+				removed = append(removed, ft.removeChild(event.Dir, event.Name)...)
+				continue
+			}
+			paired[event.Cookie] = true
+			removed = append(removed, ft.moveChild(event, to, dirty)...)
+		case WatchMoveTo:
+			if !paired[event.Cookie] {
+				ft.createChild(event.Dir, event.Name, dirty)
+			}
+		}
+	}
+
+	for node := range dirty {
+		if ft.Contains(node) {
+			SortNodes(node.Children, ft.sortOptions)
+		}
+	}
+
+	return removed
+}
+
+func (ft *FileTreeImpl) loadedDir(path string) *TreeNode {
+	node := ft.NodeAt(path)
+	if node == nil || !node.IsDir || !node.Loaded {
+		return nil
+	}
+	return node
+}
+
+func (ft *FileTreeImpl) newChild(path string) *TreeNode {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return nil
+	}
+
+	child := NewTreeNodeFromInfo(path, ft.followLink(path, info))
+	if ft.ignore != nil {
+		child.Ignored = ft.ignore(path, child.IsDir)
+	}
+	return child
+}
+
+func (ft *FileTreeImpl) createChild(dir, name string, dirty map[*TreeNode]bool) {
+	parent := ft.loadedDir(dir)
+	if parent == nil {
+		return
+	}
+
+	if existing := parent.GetChildByName(name); existing != nil {
+		ft.updateChild(dir, name, dirty)
+		return
+	}
+
+	child := ft.newChild(filepath.Join(dir, name))
+	if child == nil {
+		return
+	}
+
+	parent.AddChild(child)
+	dirty[parent] = true
+}
+
+func (ft *FileTreeImpl) removeChild(dir, name string) []*TreeNode {
+	parent := ft.loadedDir(dir)
+	if parent == nil {
+		return nil
+	}
+
+	child := parent.GetChildByName(name)
+	if child == nil {
+		return nil
+	}
+
+	parent.RemoveChild(child)
+	ft.unwatch(child)
+	return []*TreeNode{child}
+}
+
+func (ft *FileTreeImpl) updateChild(dir, name string, dirty map[*TreeNode]bool) {
+	parent := ft.loadedDir(dir)
+	if parent == nil {
+		return
+	}
+
+	child := parent.GetChildByName(name)
+	if child == nil {
+		return
+	}
+
+	info, err := os.Lstat(child.Path)
+	if err != nil {
+		return
+	}
+	info = ft.followLink(child.Path, info)
+
+	child.Size = info.Size()
+	child.ModTime = info.ModTime()
+	dirty[parent] = true
+}
+
+func (ft *FileTreeImpl) moveChild(from, to WatchEvent, dirty map[*TreeNode]bool) []*TreeNode {
+	source := ft.loadedDir(from.Dir)
+	var node *TreeNode
+	if source != nil {
+		node = source.GetChildByName(from.Name)
+	}
+	if node == nil {
+		ft.createChild(to.Dir, to.Name, dirty)
+		return nil
+	}
+
+	target := ft.loadedDir(to.Dir)
+	if target == nil {
+		// 보이지 않는 곳으로 옮겨졌으면 삭제와 같음
+		return ft.removeChild(from.Dir, from.Name)
+	}
+
+	var removed []*TreeNode
+	if existing := target.GetChildByName(to.Name); existing != nil && existing != node {
+		// 덮어쓴 대상
+		target.RemoveChild(existing)
+		ft.unwatch(existing)
+		removed = append(removed, existing)
+	}
+
+	oldPath := node.Path
+	source.RemoveChild(node)
+	node.Name = to.Name
+	target.AddChild(node)
+	node.setPath(filepath.Join(to.Dir, to.Name))
+
+	if node.IsDir && ft.watcher != nil {
+		ft.watcher.Rename(oldPath, node.Path)
+	}
+	if ft.ignore != nil {
+		node.Ignored = ft.ignore(node.Path, node.IsDir)
+	}
+
+	dirty[target] = true
+	return removed
+}
+
+// 이름이 바뀐 노드와 로드된 하위 노드의 경로를 갱신
+func (n *TreeNode) setPath(path string) {
+	n.Path = path
+	for _, child := range n.Children {
+		child.setPath(filepath.Join(path, child.Name))
+	}
+}
+
+// 디렉토리를 다시 읽어 기존 자식과 이름으로 비교해 반영. 사라진 노드를 반환
+func (ft *FileTreeImpl) syncChildren(node *TreeNode) ([]*TreeNode, error) {
+	entries, err := os.ReadDir(node.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]*TreeNode, len(node.Children))
+	for _, child := range node.Children {
+		existing[child.Name] = child
+	}
+
+	children := make([]*TreeNode, 0, len(entries))
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		info = ft.followLink(filepath.Join(node.Path, entry.Name()), info)
+
+		child, ok := existing[entry.Name()]
+		if ok && child.IsDir == info.IsDir() {
+			delete(existing, entry.Name())
+			child.Size = info.Size()
+			child.ModTime = info.ModTime()
+		} else {
+			child = NewTreeNodeFromInfo(filepath.Join(node.Path, entry.Name()), info)
+			child.Parent = node
+		}
+		if ft.ignore != nil {
+			child.Ignored = ft.ignore(child.Path, child.IsDir)
+		}
+		children = append(children, child)
+	}
+
+	var removed []*TreeNode
+	for _, child := range node.Children {
+		if existing[child.Name] == child {
+			ft.unwatch(child)
+			removed = append(removed, child)
+		}
+	}
+
+	SortNodes(children, ft.sortOptions)
+	node.Children = children
+	node.Loaded = true
+	return removed, nil
+}
+
+// 펼쳐진 하위 디렉토리를 모두 다시 읽음
+func (ft *FileTreeImpl) resyncExpanded(node *TreeNode) []*TreeNode {
+	if node == nil || !node.IsDir || !node.Loaded {
+		return nil
+	}
+
+	removed, err := ft.syncChildren(node)
+	if err != nil {
+		return nil
+	}
+
+	for _, child := range node.Children {
+		if child.Expanded {
+			removed = append(removed, ft.resyncExpanded(child)...)
+		}
+	}
+
+	return removed
+}
+
+// 무시 규칙이 바뀐 뒤 로드된 하위 노드의 Ignored 를 다시 계산
+func (ft *FileTreeImpl) UpdateIgnored(node *TreeNode) {
+	if ft.ignore == nil || node == nil {
+		return
+	}
+
+	for _, child := range node.Children {
+		child.Ignored = ft.ignore(child.Path, child.IsDir)
+		ft.UpdateIgnored(child)
+	}
+}
+
+// 펼쳐진 노드와 그 아래 펼쳐진 디렉토리를 감시 대상에 추가
+func (ft *FileTreeImpl) watch(node *TreeNode) {
+	if ft.watcher == nil || !node.Expanded {
+		return
+	}
+
+	ft.watcher.Add(node.Path)
+	for _, child := range node.Children {
+		ft.watch(child)
+	}
+}
+
+func (ft *FileTreeImpl) unwatch(node *TreeNode) {
+	if ft.watcher == nil || !node.IsDir {
+		return
+	}
+
+	ft.watcher.Remove(node.Path)
+	for _, child := range node.Children {
+		ft.unwatch(child)
+	}
+}
@@ -3,6 +3,7 @@ package filetree
 import (
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strings"
 )
 
@@ -10,32 +11,112 @@ type Walker struct {
 	tree *FileTreeImpl
 }
 
+// 화면에 보일 노드를 고르는 옵션. Sort 가 트리의 정렬과 다르면 보일 순서만 바꾸고 트리는 그대로 둠
+type ViewOptions struct {
+	ShowHidden  bool
+	ShowIgnored bool
+	Filter      string
+	Sort        SortOptions
+}
+
 func NewWalker(tree *FileTreeImpl) *Walker {
 	return &Walker{tree: tree}
 }
 
-func (w *Walker) GetVisibleNodes() []*TreeNode {
-	var visible []*TreeNode
+func (w *Walker) GetVisibleNodes(opts ViewOptions) []*TreeNode {
 	if w.tree.root == nil {
-		return visible
+		return nil
+	}
+
+	walk := &visibleWalk{
+		opts:    opts,
+		filter:  newNameFilter(opts.Filter),
+		resort:  opts.Sort != w.tree.sortOptions,
+		matches: make(map[*TreeNode]bool),
 	}
+	walk.collect(w.tree.root)
+	return walk.nodes
+}
 
-	w.collectVisible(w.tree.root, &visible)
-	return visible
+// GetVisibleNodes 한 번 동안의 상태. 필터 판정은 노드마다 한 번만 계산해 둠
+type visibleWalk struct {
+	opts    ViewOptions
+	filter  *nameFilter
+	resort  bool
+	matches map[*TreeNode]bool
+	nodes   []*TreeNode
 }
 
-func (w *Walker) collectVisible(node *TreeNode, visible *[]*TreeNode) {
-	*visible = append(*visible, node)
+func (v *visibleWalk) collect(node *TreeNode) {
+	v.nodes = append(v.nodes, node)
+	if !node.Expanded || !node.IsDir {
+		return
+	}
+
+	children := node.Children
+	if v.resort {
+		children = slices.Clone(children)
+		SortNodes(children, v.opts.Sort)
+	}
+	for _, child := range children {
+		if v.isVisible(child) {
+			v.collect(child)
+		}
+	}
+}
+
+func (v *visibleWalk) isVisible(node *TreeNode) bool {
+	if !v.opts.ShowHidden && node.IsHidden() {
+		return false
+	}
+	if !v.opts.ShowIgnored && node.Ignored {
+		return false
+	}
+	if v.filter == nil {
+		return true
+	}
 
-	if node.Expanded && node.IsDir {
+	// 필터와 맞지 않는 하위 트리는 잘라내되 일치하는 노드의 조상은 남김
+	if matched, ok := v.matches[node]; ok {
+		return matched
+	}
+	matched := v.filter.match(node.Name)
+	if !matched && node.IsDir && node.Loaded {
 		for _, child := range node.Children {
-			w.collectVisible(child, visible)
+			if v.isVisible(child) {
+				matched = true
+				break
+			}
 		}
 	}
+	v.matches[node] = matched
+	return matched
+}
+
+// 대문자가 포함된 경우에만 대소문자를 구분하는 부분 문자열 필터
+type nameFilter struct {
+	text          string
+	caseSensitive bool
+}
+
+func newNameFilter(text string) *nameFilter {
+	if text == "" {
+		return nil
+	}
+
+	caseSensitive := strings.ToLower(text) != text
+	return &nameFilter{text: text, caseSensitive: caseSensitive}
+}
+
+func (f *nameFilter) match(name string) bool {
+	if f.caseSensitive {
+		return strings.Contains(name, f.text)
+	}
+	return strings.Contains(strings.ToLower(name), f.text)
 }
 
-func (w *Walker) GetNextVisibleNode(current *TreeNode) *TreeNode {
-	visible := w.GetVisibleNodes()
+func (w *Walker) GetNextVisibleNode(current *TreeNode, opts ViewOptions) *TreeNode {
+	visible := w.GetVisibleNodes(opts)
 
 	for i, node := range visible {
 		if node == current && i+1 < len(visible) {
@@ -46,8 +127,8 @@ func (w *Walker) GetNextVisibleNode(current *TreeNode) *TreeNode {
 	return nil
 }
 
-func (w *Walker) GetPrevVisibleNode(current *TreeNode) *TreeNode {
-	visible := w.GetVisibleNodes()
+func (w *Walker) GetPrevVisibleNode(current *TreeNode, opts ViewOptions) *TreeNode {
+	visible := w.GetVisibleNodes(opts)
 
 	for i, node := range visible {
 		if node == current && i > 0 {
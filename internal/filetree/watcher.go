@@ -0,0 +1,28 @@
+package filetree
+
+type WatchOp int
+
+const (
+	WatchCreate WatchOp = iota
+	WatchRemove
+	WatchMoveFrom
+	WatchMoveTo
+	WatchChange
+	// 커널 이벤트 큐가 넘쳐 일부 이벤트를 잃음. 전체 다시 읽기 필요
+	WatchOverflow
+)
+
+type WatchEvent struct {
+	Dir    string
+	Name   string
+	Op     WatchOp
+	IsDir  bool
+	Cookie uint32 // MoveFrom/MoveTo 짝을 맞추는 값
+}
+
+// 펼쳐진 디렉토리를 감시하는 쪽이 구현
+type DirWatcher interface {
+	Add(dir string) error
+	Remove(dir string) error
+	Rename(oldPath, newPath string)
+}
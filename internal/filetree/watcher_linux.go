@@ -0,0 +1,233 @@
+//go:build linux
+
+package filetree
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const watchMask = unix.IN_CREATE | unix.IN_DELETE | unix.IN_MOVED_FROM | unix.IN_MOVED_TO |
+	unix.IN_CLOSE_WRITE | unix.IN_ATTRIB | unix.IN_ONLYDIR
+
+// inotify 기반 감시자. 이벤트는 debounce 동안 모아서 한 번에 전달
+type Watcher struct {
+	file *os.File
+	fd   int
+
+	mu    sync.Mutex
+	paths map[int]string
+	wds   map[string]int
+	// 직전에 옮겨진 디렉토리. 커널은 MOVED_FROM 바로 뒤에 MOVED_TO 를 보냄
+	movingCookie uint32
+	movingPath   string
+
+	raw    chan WatchEvent
+	events chan []WatchEvent
+	done   chan struct{}
+}
+
+func NewWatcher(debounce time.Duration) (*Watcher, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Watcher{
+		// non-blocking fd 를 os.File 로 감싸면 런타임 poller 를 사용하므로 Close 로 Read 를 깨울 수 있음
+		file:   os.NewFile(uintptr(fd), "inotify"),
+		fd:     fd,
+		paths:  make(map[int]string),
+		wds:    make(map[string]int),
+		raw:    make(chan WatchEvent, 256),
+		events: make(chan []WatchEvent),
+		done:   make(chan struct{}),
+	}
+
+	go w.readLoop()
+	go w.debounceLoop(debounce)
+
+	return w, nil
+}
+
+func (w *Watcher) Events() <-chan []WatchEvent {
+	return w.events
+}
+
+func (w *Watcher) Add(dir string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.wds[dir]; ok {
+		return nil
+	}
+
+	wd, err := unix.InotifyAddWatch(w.fd, dir, watchMask)
+	if err != nil {
+		return err
+	}
+
+	w.paths[wd] = dir
+	w.wds[dir] = wd
+	return nil
+}
+
+func (w *Watcher) Remove(dir string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	wd, ok := w.wds[dir]
+	if !ok {
+		return nil
+	}
+
+	delete(w.wds, dir)
+	delete(w.paths, wd)
+
+	_, err := unix.InotifyRmWatch(w.fd, uint32(wd))
+	return err
+}
+
+// 감시 중인 디렉토리가 이름을 바꾸면 그 아래 감시 경로도 함께 갱신
+func (w *Watcher) Rename(oldPath, newPath string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.rename(oldPath, newPath)
+}
+
+func (w *Watcher) rename(oldPath, newPath string) {
+	for path, wd := range w.wds {
+		if path != oldPath && !strings.HasPrefix(path, oldPath+string(os.PathSeparator)) {
+			continue
+		}
+
+		renamed := newPath + strings.TrimPrefix(path, oldPath)
+		delete(w.wds, path)
+		w.wds[renamed] = wd
+		w.paths[wd] = renamed
+	}
+}
+
+func (w *Watcher) Close() error {
+	select {
+	case <-w.done:
+		return nil
+	default:
+	}
+
+	close(w.done)
+	return w.file.Close()
+}
+
+func (w *Watcher) readLoop() {
+	buffer := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+
+	for {
+		n, err := w.file.Read(buffer)
+		if err != nil {
+			return
+		}
+
+		for offset := 0; offset+unix.SizeofInotifyEvent <= n; {
+			raw := (*unix.InotifyEvent)(unsafe.Pointer(&buffer[offset]))
+			nameBytes := buffer[offset+unix.SizeofInotifyEvent : offset+unix.SizeofInotifyEvent+int(raw.Len)]
+			offset += unix.SizeofInotifyEvent + int(raw.Len)
+
+			event, ok := w.convert(raw, string(bytes.TrimRight(nameBytes, "\x00")))
+			if !ok {
+				continue
+			}
+
+			select {
+			case w.raw <- event:
+			case <-w.done:
+				return
+			}
+		}
+	}
+}
+
+func (w *Watcher) convert(raw *unix.InotifyEvent, name string) (WatchEvent, bool) {
+	if raw.Mask&unix.IN_Q_OVERFLOW != 0 {
+		return WatchEvent{Op: WatchOverflow}, true
+	}
+
+	w.mu.Lock()
+	dir, ok := w.paths[int(raw.Wd)]
+	if raw.Mask&unix.IN_IGNORED != 0 && ok {
+		// 디렉토리가 삭제되어 커널이 감시를 해제함
+		delete(w.paths, int(raw.Wd))
+		delete(w.wds, dir)
+		ok = false
+	}
+	if ok && raw.Mask&unix.IN_ISDIR != 0 {
+		// 뒤따르는 이벤트가 새 경로로 나오도록 읽는 즉시 감시 경로를 옮김
+		path := filepath.Join(dir, name)
+		if raw.Mask&unix.IN_MOVED_FROM != 0 {
+			w.movingCookie, w.movingPath = raw.Cookie, path
+		} else if raw.Mask&unix.IN_MOVED_TO != 0 && raw.Cookie == w.movingCookie {
+			w.rename(w.movingPath, path)
+			w.movingCookie, w.movingPath = 0, ""
+		}
+	}
+	w.mu.Unlock()
+
+	if !ok || name == "" {
+		return WatchEvent{}, false
+	}
+
+	event := WatchEvent{
+		Dir:    dir,
+		Name:   name,
+		IsDir:  raw.Mask&unix.IN_ISDIR != 0,
+		Cookie: raw.Cookie,
+	}
+
+	switch {
+	case raw.Mask&unix.IN_CREATE != 0:
+		event.Op = WatchCreate
+	case raw.Mask&unix.IN_DELETE != 0:
+		event.Op = WatchRemove
+	case raw.Mask&unix.IN_MOVED_FROM != 0:
+		event.Op = WatchMoveFrom
+	case raw.Mask&unix.IN_MOVED_TO != 0:
+		event.Op = WatchMoveTo
+	default:
+		event.Op = WatchChange
+	}
+
+	return event, true
+}
+
+func (w *Watcher) debounceLoop(debounce time.Duration) {
+	var pending []WatchEvent
+	var timer <-chan time.Time
+	var out chan []WatchEvent
+
+	for {
+		select {
+		case event := <-w.raw:
+			pending = append(pending, event)
+			if timer == nil {
+				timer = time.After(debounce)
+			}
+		case <-timer:
+			// 모인 이벤트를 UI 쪽이 받을 수 있을 때 전달
+			timer = nil
+			out = w.events
+		case out <- pending:
+			pending = nil
+			out = nil
+		case <-w.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,21 @@
+//go:build !linux
+
+package filetree
+
+import (
+	"errors"
+	"time"
+)
+
+// inotify 가 없는 플랫폼에서는 감시하지 않음
+type Watcher struct{}
+
+func NewWatcher(debounce time.Duration) (*Watcher, error) {
+	return nil, errors.New("file watching is not supported on this platform")
+}
+
+func (w *Watcher) Events() <-chan []WatchEvent    { return nil }
+func (w *Watcher) Add(dir string) error           { return nil }
+func (w *Watcher) Remove(dir string) error        { return nil }
+func (w *Watcher) Rename(oldPath, newPath string) {}
+func (w *Watcher) Close() error                   { return nil }
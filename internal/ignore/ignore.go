@@ -0,0 +1,200 @@
+package ignore
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// 디렉토리마다 읽는 무시 파일. 뒤의 파일이 우선
+var ignoreFiles = []string{".gitignore", ".ignore", ".twfignore"}
+
+// 루트 아래 경로에 gitignore 규칙을 적용. 여러 고루틴에서 동시에 사용 가능
+type Matcher struct {
+	root string
+	// 전역 excludes 파일과 설정의 추가 glob (가장 낮은 우선순위)
+	global []rule
+
+	mu       sync.Mutex
+	dirRules map[string][]rule
+}
+
+func NewMatcher(root string, extraGlobs []string) *Matcher {
+	m := &Matcher{
+		root:     root,
+		dirRules: make(map[string][]rule),
+	}
+
+	if excludesFile := globalExcludesFile(); excludesFile != "" {
+		m.global = append(m.global, readRules(excludesFile, "")...)
+	}
+	for _, glob := range extraGlobs {
+		if r, ok := parseRule(glob, ""); ok {
+			m.global = append(m.global, r)
+		}
+	}
+
+	return m
+}
+
+// 경로 자체나 조상 디렉토리가 무시 대상이면 true
+func (m *Matcher) Ignored(path string, isDir bool) bool {
+	rel, err := filepath.Rel(m.root, path)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+	rel = filepath.ToSlash(rel)
+
+	parts := strings.Split(rel, "/")
+	for i := 1; i < len(parts); i++ {
+		if m.match(strings.Join(parts[:i], "/"), true) {
+			return true
+		}
+	}
+
+	return m.match(rel, isDir)
+}
+
+// 마지막으로 매칭된 규칙이 결과를 결정
+func (m *Matcher) match(rel string, isDir bool) bool {
+	ignored := false
+
+	apply := func(rules []rule) {
+		for _, r := range rules {
+			if r.match(rel, isDir) {
+				ignored = !r.negate
+			}
+		}
+	}
+
+	apply(m.global)
+
+	dir := ""
+	apply(m.rulesFor(dir))
+	parts := strings.Split(rel, "/")
+	for _, part := range parts[:len(parts)-1] {
+		if dir == "" {
+			dir = part
+		} else {
+			dir += "/" + part
+		}
+		apply(m.rulesFor(dir))
+	}
+
+	return ignored
+}
+
+func (m *Matcher) rulesFor(dir string) []rule {
+	m.mu.Lock()
+	rules, ok := m.dirRules[dir]
+	m.mu.Unlock()
+	if ok {
+		return rules
+	}
+
+	for _, name := range ignoreFiles {
+		path := filepath.Join(m.root, filepath.FromSlash(dir), name)
+		rules = append(rules, readRules(path, dir)...)
+	}
+
+	m.mu.Lock()
+	m.dirRules[dir] = rules
+	m.mu.Unlock()
+
+	return rules
+}
+
+// 디렉토리 내용이 바뀌었을 때 (.gitignore 수정 등) 캐시를 비움
+func (m *Matcher) Invalidate(path string) {
+	rel, err := filepath.Rel(m.root, path)
+	if err != nil {
+		return
+	}
+	rel = filepath.ToSlash(rel)
+	if rel == "." {
+		rel = ""
+	}
+
+	m.mu.Lock()
+	delete(m.dirRules, rel)
+	m.mu.Unlock()
+}
+
+// 무시 규칙을 담는 파일 이름인지 확인
+func IsIgnoreFile(name string) bool {
+	return slices.Contains(ignoreFiles, name)
+}
+
+func readRules(path, base string) []rule {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var rules []rule
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if r, ok := parseRule(scanner.Text(), base); ok {
+			rules = append(rules, r)
+		}
+	}
+
+	return rules
+}
+
+// git 의 core.excludesFile, 없으면 $XDG_CONFIG_HOME/git/ignore
+func globalExcludesFile() string {
+	home, _ := os.UserHomeDir()
+
+	if home != "" {
+		if path := excludesFileFromGitConfig(filepath.Join(home, ".gitconfig")); path != "" {
+			if strings.HasPrefix(path, "~/") {
+				path = filepath.Join(home, path[2:])
+			}
+			return path
+		}
+	}
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		if home == "" {
+			return ""
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+
+	return filepath.Join(configHome, "git", "ignore")
+}
+
+func excludesFileFromGitConfig(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	inCore := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if strings.HasPrefix(line, "[") {
+			inCore = strings.EqualFold(strings.Trim(line, "[] \t"), "core")
+			continue
+		}
+		if !inCore {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if ok && strings.EqualFold(strings.TrimSpace(key), "excludesfile") {
+			return strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,108 @@
+package ignore
+
+import (
+	"path"
+	"strings"
+)
+
+// gitignore 한 줄
+type rule struct {
+	segments []string
+	negate   bool
+	dirOnly  bool
+	// 슬래시가 없는 패턴은 어느 깊이의 이름과도 매칭
+	basenameOnly bool
+	// 규칙이 정의된 디렉토리 (루트 기준, 루트는 "")
+	base string
+}
+
+func parseRule(line, base string) (rule, bool) {
+	line = strings.TrimRight(line, "\r")
+	line = trimTrailingSpaces(line)
+
+	if line == "" || strings.HasPrefix(line, "#") {
+		return rule{}, false
+	}
+
+	r := rule{base: base}
+
+	if strings.HasPrefix(line, "!") {
+		r.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		r.dirOnly = true
+		line = strings.TrimSuffix(line, "/")
+	}
+
+	if line == "" {
+		return rule{}, false
+	}
+
+	// 앞이나 중간에 슬래시가 있으면 base 기준으로 고정
+	r.basenameOnly = !strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	r.segments = strings.Split(line, "/")
+	return r, true
+}
+
+func trimTrailingSpaces(line string) string {
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-1]
+	}
+	return strings.ReplaceAll(line, `\ `, " ")
+}
+
+// rel 은 루트 기준 슬래시 경로
+func (r rule) match(rel string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+
+	if r.base != "" {
+		if !strings.HasPrefix(rel, r.base+"/") {
+			return false
+		}
+		rel = strings.TrimPrefix(rel, r.base+"/")
+	}
+
+	if r.basenameOnly {
+		ok, _ := path.Match(r.segments[0], path.Base(rel))
+		return ok
+	}
+
+	return matchSegments(r.segments, strings.Split(rel, "/"))
+}
+
+// ** 는 0개 이상의 경로 요소와 매칭
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			if len(rest) == 0 {
+				return len(parts) > 0
+			}
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(rest, parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+			return false
+		}
+
+		pattern, parts = pattern[1:], parts[1:]
+	}
+
+	return len(parts) == 0
+}
@@ -0,0 +1,343 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/minimal1/twf-clone/internal/fileops"
+)
+
+type Kind int
+
+const (
+	KindCopy Kind = iota
+	KindMove
+	KindDelete
+	KindChmod
+)
+
+var kindNames = []string{"copy", "move", "delete", "chmod"}
+
+func (k Kind) String() string {
+	return kindNames[k]
+}
+
+type Status int
+
+const (
+	StatusRunning Status = iota
+	StatusDone
+	StatusFailed
+	StatusCancelled
+)
+
+var statusNames = []string{"running", "done", "failed", "cancelled"}
+
+func (s Status) String() string {
+	return statusNames[s]
+}
+
+// 작업 대상 하나. Dst 는 복사/이동과 휴지통으로 옮기는 삭제에서, Mode 는 chmod 에서 사용
+type Task struct {
+	Src  string
+	Dst  string
+	Mode os.FileMode
+
+	// Backup 이 있으면 새 항목이 준비된 뒤 Dst 에 있던 항목을 그 자리로 옮김
+	Backup string
+	// Restore 가 있으면 작업이 끝난 뒤 그 항목을 Src 자리로 옮김 (덮어쓴 항목을 되살릴 때)
+	Restore string
+}
+
+type Spec struct {
+	Kind  Kind
+	Tasks []Task
+}
+
+type FileError struct {
+	Path string
+	Err  error
+}
+
+// 백그라운드에서 실행 중인 파일 작업. 진행 상황은 Snapshot 으로 읽음
+type Job struct {
+	ID    int
+	Kind  Kind
+	Title string
+	Tasks []Task
+
+	cancel context.CancelFunc
+
+	files      atomic.Int64
+	totalFiles atomic.Int64
+	bytes      atomic.Int64
+	totalBytes atomic.Int64
+
+	mu        sync.Mutex
+	status    Status
+	errors    []FileError
+	completed []int
+	started   time.Time
+	finished  time.Time
+}
+
+func (j *Job) AddFiles(n int64) { j.files.Add(n) }
+func (j *Job) AddBytes(n int64) { j.bytes.Add(n) }
+
+func (j *Job) Cancel() {
+	j.cancel()
+}
+
+func (j *Job) Status() Status {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.status
+}
+
+// 성공한 Task 의 인덱스
+func (j *Job) Completed() []int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return append([]int(nil), j.completed...)
+}
+
+// 그리기용으로 복사한 작업 상태
+type Snapshot struct {
+	ID         int
+	Kind       Kind
+	Title      string
+	Status     Status
+	Files      int64
+	TotalFiles int64
+	Bytes      int64
+	TotalBytes int64
+	Elapsed    time.Duration
+	Errors     []FileError
+}
+
+func (j *Job) Snapshot() Snapshot {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	end := j.finished
+	if end.IsZero() {
+		end = time.Now()
+	}
+
+	return Snapshot{
+		ID:         j.ID,
+		Kind:       j.Kind,
+		Title:      j.Title,
+		Status:     j.status,
+		Files:      j.files.Load(),
+		TotalFiles: j.totalFiles.Load(),
+		Bytes:      j.bytes.Load(),
+		TotalBytes: j.totalBytes.Load(),
+		Elapsed:    end.Sub(j.started),
+		Errors:     append([]FileError(nil), j.errors...),
+	}
+}
+
+// 0~1 사이 진행률. 크기를 알면 바이트 기준, 아니면 파일 수 기준
+func (s Snapshot) Fraction() float64 {
+	switch {
+	case s.TotalBytes > 0:
+		return min(float64(s.Bytes)/float64(s.TotalBytes), 1)
+	case s.TotalFiles > 0:
+		return min(float64(s.Files)/float64(s.TotalFiles), 1)
+	case s.Status != StatusRunning:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// 초당 처리한 바이트
+func (s Snapshot) Throughput() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Bytes) / s.Elapsed.Seconds()
+}
+
+type Manager struct {
+	mu       sync.Mutex
+	jobs     []*Job
+	finished []*Job
+	running  int
+	nextID   int
+	wg       sync.WaitGroup
+}
+
+func NewManager() *Manager {
+	return &Manager{nextID: 1}
+}
+
+func (m *Manager) Start(spec Spec) *Job {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	m.mu.Lock()
+	job := &Job{
+		ID:      m.nextID,
+		Kind:    spec.Kind,
+		Title:   title(spec),
+		Tasks:   spec.Tasks,
+		cancel:  cancel,
+		status:  StatusRunning,
+		started: time.Now(),
+	}
+	m.nextID++
+	m.jobs = append(m.jobs, job)
+	m.running++
+	m.mu.Unlock()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		m.run(ctx, job, spec)
+	}()
+	return job
+}
+
+func title(spec Spec) string {
+	if len(spec.Tasks) == 1 {
+		return filepath.Base(spec.Tasks[0].Src)
+	}
+	return fmt.Sprintf("%d items", len(spec.Tasks))
+}
+
+func (m *Manager) run(ctx context.Context, job *Job, spec Spec) {
+	srcs := make([]string, len(spec.Tasks))
+	for i, task := range spec.Tasks {
+		srcs[i] = task.Src
+	}
+	files, bytes := fileops.Measure(srcs)
+	if spec.Kind == KindChmod {
+		files, bytes = int64(len(spec.Tasks)), 0
+	}
+	job.totalFiles.Store(files)
+	job.totalBytes.Store(bytes)
+
+	var errs []FileError
+	var completed []int
+	for i, task := range spec.Tasks {
+		err := runTask(ctx, spec.Kind, task, job)
+		if err == nil {
+			completed = append(completed, i)
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		if err != nil {
+			errs = append(errs, FileError{Path: task.Src, Err: err})
+		}
+	}
+
+	status := StatusDone
+	switch {
+	case errors.Is(ctx.Err(), context.Canceled):
+		status = StatusCancelled
+	case len(errs) > 0:
+		status = StatusFailed
+	}
+	job.cancel()
+
+	// 상태, 끝난 목록, 실행 중인 수를 한 번에 바꿔서 TakeFinished 가 중간 상태를 보지 않게 함
+	m.mu.Lock()
+	job.mu.Lock()
+	job.status = status
+	job.errors = errs
+	job.completed = completed
+	job.finished = time.Now()
+	job.mu.Unlock()
+
+	m.finished = append(m.finished, job)
+	m.running--
+	m.mu.Unlock()
+}
+
+func runTask(ctx context.Context, kind Kind, task Task, job *Job) error {
+	var err error
+	switch kind {
+	case KindCopy:
+		err = fileops.CopyReplacing(ctx, task.Src, task.Dst, task.Backup, job)
+	case KindMove:
+		err = fileops.MoveReplacing(ctx, task.Src, task.Dst, task.Backup, job)
+	case KindDelete:
+		if task.Dst != "" {
+			err = fileops.Move(ctx, task.Src, task.Dst, job)
+		} else {
+			err = fileops.Remove(ctx, task.Src, job)
+		}
+	case KindChmod:
+		err = fileops.Chmod(ctx, task.Src, task.Mode, job)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if task.Restore != "" {
+		return fileops.Move(context.Background(), task.Restore, task.Src, nil)
+	}
+	return nil
+}
+
+// 시작한 순서대로 모든 작업
+func (m *Manager) Jobs() []*Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*Job(nil), m.jobs...)
+}
+
+func (m *Manager) Snapshots() []Snapshot {
+	jobs := m.Jobs()
+	snapshots := make([]Snapshot, len(jobs))
+	for i, job := range jobs {
+		snapshots[i] = job.Snapshot()
+	}
+	return snapshots
+}
+
+func (m *Manager) Running() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.running
+}
+
+func (m *Manager) Get(id int) *Job {
+	for _, job := range m.Jobs() {
+		if job.ID == id {
+			return job
+		}
+	}
+	return nil
+}
+
+func (m *Manager) CancelAll() {
+	for _, job := range m.Jobs() {
+		job.Cancel()
+	}
+}
+
+// 모든 작업이 끝날 때까지 기다림. 종료 전에 임시 파일이 정리되도록 함
+func (m *Manager) Wait() {
+	m.wg.Wait()
+}
+
+// 마지막으로 호출한 뒤 끝난 작업들과 지금 실행 중인 작업 수.
+// 같은 잠금 안에서 읽으므로 running 이 0 이면 끝난 작업은 모두 finished 에 들어 있음
+func (m *Manager) TakeFinished() (finished []*Job, running int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	finished = m.finished
+	m.finished = nil
+	return finished, m.running
+}
@@ -0,0 +1,85 @@
+package preview
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// 외부 명령 미리보기. 명령 안의 {} 는 따옴표로 감싼 경로로 치환
+type CommandPreviewer struct {
+	Command string
+	Timeout time.Duration
+}
+
+func NewCommandPreviewer(command string, timeout time.Duration) *CommandPreviewer {
+	return &CommandPreviewer{
+		Command: command,
+		Timeout: timeout,
+	}
+}
+
+// 명령이 끝난 뒤 하위 프로세스가 출력을 붙잡고 있어도 기다리는 최대 시간
+const commandWaitDelay = 100 * time.Millisecond
+
+// 한 칸에 색 코드가 붙어도 미리보기 영역을 채울 수 있는 바이트 수
+const bytesPerCell = 16
+
+func (p *CommandPreviewer) Preview(ctx context.Context, req Request) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.Timeout)
+	defer cancel()
+
+	command := strings.ReplaceAll(p.Command, "{}", ShellQuote(req.Path))
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(),
+		fmt.Sprintf("COLUMNS=%d", req.Width),
+		fmt.Sprintf("LINES=%d", req.Height),
+	)
+	cmd.WaitDelay = commandWaitDelay
+
+	reader, writer := io.Pipe()
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		err := cmd.Wait()
+		writer.Close()
+		done <- err
+	}()
+
+	// 미리보기 영역을 채울 만큼만 읽음
+	limit := int64(req.Height) * int64(req.Width+1) * bytesPerCell
+	var lines []string
+	scanner := bufio.NewScanner(io.LimitReader(reader, limit))
+	for len(lines) < req.Height && scanner.Scan() {
+		lines = append(lines, Sanitize(scanner.Text()))
+	}
+
+	timedOut := ctx.Err() == context.DeadlineExceeded
+	// 나머지 출력은 필요 없으므로 명령을 끝냄
+	reader.Close()
+	cancel()
+	err := <-done
+	if timedOut {
+		return nil, fmt.Errorf("preview command timed out after %s", p.Timeout)
+	}
+
+	if err != nil && len(lines) == 0 {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,62 @@
+package preview
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type DirPreviewer struct{}
+
+func (p *DirPreviewer) Preview(ctx context.Context, req Request) ([]string, error) {
+	entries, err := os.ReadDir(req.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	dirCount, fileCount := 0, 0
+	var totalSize int64
+	listing := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if !req.ShowHidden && strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		if entry.IsDir() {
+			dirCount++
+			listing = append(listing, Sanitize(entry.Name())+"/")
+			continue
+		}
+
+		fileCount++
+		if info, err := entry.Info(); err == nil {
+			totalSize += info.Size()
+		}
+		listing = append(listing, Sanitize(entry.Name()))
+	}
+
+	summary := fmt.Sprintf("%d directories, %d files, %s", dirCount, fileCount, FormatSize(totalSize))
+	lines := []string{summary, ""}
+
+	return append(lines, listing...), nil
+}
+
+func FormatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,65 @@
+package preview
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+type HexPreviewer struct{}
+
+func (p *HexPreviewer) Preview(ctx context.Context, req Request) ([]string, error) {
+	file, err := os.Open(req.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	perLine := 16
+	if req.Width < 76 {
+		perLine = 8
+	}
+
+	buffer := make([]byte, perLine*req.Height)
+	n, err := io.ReadFull(file, buffer)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+
+	var lines []string
+	for offset := 0; offset < n; offset += perLine {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		end := min(offset+perLine, n)
+		lines = append(lines, hexLine(offset, buffer[offset:end], perLine))
+	}
+
+	return lines, nil
+}
+
+func hexLine(offset int, chunk []byte, perLine int) string {
+	var hexPart, asciiPart strings.Builder
+
+	for i := range perLine {
+		if i < len(chunk) {
+			fmt.Fprintf(&hexPart, "%02x ", chunk[i])
+		} else {
+			hexPart.WriteString("   ")
+		}
+
+		if i < len(chunk) {
+			b := chunk[i]
+			if b >= 0x20 && b < 0x7f {
+				asciiPart.WriteByte(b)
+			} else {
+				asciiPart.WriteByte('.')
+			}
+		}
+	}
+
+	return fmt.Sprintf("%08x  %s |%s|", offset, hexPart.String(), asciiPart.String())
+}
@@ -0,0 +1,129 @@
+package preview
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+type Request struct {
+	Path        string
+	IsDir       bool
+	Width       int
+	Height      int
+	LineNumbers bool
+	ShowHidden  bool
+}
+
+type Result struct {
+	Path  string
+	Lines []string
+	Err   error
+	// 어느 요청의 결과인지. IsCurrent 로 지난 요청의 결과를 거름
+	Seq uint64
+}
+
+type Previewer interface {
+	Preview(ctx context.Context, req Request) ([]string, error)
+}
+
+// 요청마다 알맞은 Previewer 를 골라 UI 고루틴 밖에서 실행
+type Manager struct {
+	command Previewer
+	dir     Previewer
+	text    Previewer
+	binary  Previewer
+
+	results chan Result
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	seq    uint64
+}
+
+func NewManager(command Previewer) *Manager {
+	return &Manager{
+		command: command,
+		dir:     &DirPreviewer{},
+		text:    &TextPreviewer{},
+		binary:  &HexPreviewer{},
+		results: make(chan Result, 1),
+	}
+}
+
+func (m *Manager) Results() <-chan Result {
+	return m.results
+}
+
+// 이전 요청을 취소하고 새 미리보기를 시작
+func (m *Manager) Request(req Request) {
+	m.mu.Lock()
+	if m.cancel != nil {
+		m.cancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.seq++
+	seq := m.seq
+	m.mu.Unlock()
+
+	go func() {
+		lines, err := m.previewerFor(req).Preview(ctx, req)
+		if ctx.Err() != nil {
+			return
+		}
+
+		select {
+		case m.results <- Result{Path: req.Path, Lines: lines, Err: err, Seq: seq}:
+		case <-ctx.Done():
+		}
+	}()
+}
+
+func (m *Manager) Cancel() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.cancel != nil {
+		m.cancel()
+		m.cancel = nil
+	}
+	m.seq++
+}
+
+// 취소 직전에 채널에 들어간 결과는 받는 쪽에서 버려야 함
+func (m *Manager) IsCurrent(result Result) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return result.Seq == m.seq
+}
+
+func (m *Manager) previewerFor(req Request) Previewer {
+	if m.command != nil {
+		return m.command
+	}
+	if req.IsDir {
+		return m.dir
+	}
+	if isBinaryFile(req.Path) {
+		return m.binary
+	}
+	return m.text
+}
+
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b[@-_]`)
+
+// 화면을 깨뜨리는 제어 문자를 제거하고 탭을 공백으로 변환
+func Sanitize(line string) string {
+	line = ansiPattern.ReplaceAllString(line, "")
+	line = strings.ReplaceAll(line, "\t", "    ")
+
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, line)
+}
@@ -0,0 +1,76 @@
+package preview
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"unicode/utf8"
+)
+
+const sniffSize = 8000
+
+type TextPreviewer struct{}
+
+func (p *TextPreviewer) Preview(ctx context.Context, req Request) ([]string, error) {
+	file, err := os.Open(req.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for len(lines) < req.Height && scanner.Scan() {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		lines = append(lines, Sanitize(scanner.Text()))
+	}
+	if err := scanner.Err(); err != nil && err != bufio.ErrTooLong {
+		return nil, err
+	}
+
+	if req.LineNumbers {
+		numberWidth := len(strconv.Itoa(len(lines)))
+		for i, line := range lines {
+			lines[i] = fmt.Sprintf("%*d │ %s", numberWidth, i+1, line)
+		}
+	}
+
+	return lines, nil
+}
+
+// 앞부분에 NUL 이 있거나 UTF-8 이 아니면 바이너리로 판단
+func isBinaryFile(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	buffer := make([]byte, sniffSize)
+	n, _ := file.Read(buffer)
+	return isBinary(buffer[:n])
+}
+
+func isBinary(data []byte) bool {
+	for _, b := range data {
+		if b == 0 {
+			return true
+		}
+	}
+
+	// 잘린 마지막 문자는 무시
+	for i := 0; i < utf8.UTFMax && len(data) > 0; i++ {
+		if utf8.Valid(data) {
+			return false
+		}
+		data = data[:len(data)-1]
+	}
+
+	return !utf8.Valid(data)
+}
@@ -0,0 +1,23 @@
+package search
+
+// 검색 대상 경로. Rel 은 루트 기준 상대 경로 (매칭에 사용)
+type Entry struct {
+	Rel    string
+	Path   string
+	IsDir  bool
+	Hidden bool // 경로 중 하나라도 . 으로 시작
+}
+
+func VisibleEntries(entries []Entry, showHidden bool) []Entry {
+	if showHidden {
+		return entries
+	}
+
+	visible := make([]Entry, 0, len(entries))
+	for _, e := range entries {
+		if !e.Hidden {
+			visible = append(visible, e)
+		}
+	}
+	return visible
+}
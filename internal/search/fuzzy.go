@@ -0,0 +1,177 @@
+package search
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// fzf 와 비슷한 점수 체계
+const (
+	scoreMatch        = 16
+	bonusBoundary     = 8
+	bonusPathBoundary = 10
+	bonusCamelCase    = 7
+	bonusConsecutive  = 5
+	bonusFirstChar    = 4
+	penaltyGapStart   = 3
+	penaltyGapExtend  = 1
+)
+
+type Result struct {
+	Entry
+	Score     int
+	Positions []int // 일치한 문자(rune)의 위치
+}
+
+// pattern 의 모든 문자가 순서대로 text 에 있으면 점수와 위치를 반환.
+// 대문자가 포함된 pattern 만 대소문자를 구분 (smart case)
+func Match(pattern, text string) (int, []int, bool) {
+	if pattern == "" {
+		return 0, nil, true
+	}
+
+	caseSensitive := strings.ToLower(pattern) != pattern
+	patternRunes := []rune(pattern)
+	textRunes := []rune(text)
+
+	fold := func(r rune) rune {
+		if caseSensitive {
+			return r
+		}
+		return unicode.ToLower(r)
+	}
+
+	// 앞에서부터 처음 일치하는 끝 위치를 찾고
+	pi, end := 0, -1
+	for ti, r := range textRunes {
+		if fold(r) == patternRunes[pi] {
+			pi++
+			if pi == len(patternRunes) {
+				end = ti
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return 0, nil, false
+	}
+
+	// 뒤에서부터 다시 훑어 가장 짧은 구간을 선택
+	positions := make([]int, len(patternRunes))
+	pi = len(patternRunes) - 1
+	for ti := end; ti >= 0 && pi >= 0; ti-- {
+		if fold(textRunes[ti]) == patternRunes[pi] {
+			positions[pi] = ti
+			pi--
+		}
+	}
+
+	return score(textRunes, positions), positions, true
+}
+
+func score(text []rune, positions []int) int {
+	total := 0
+
+	for i, pos := range positions {
+		total += scoreMatch + boundaryBonus(text, pos)
+
+		if i == 0 {
+			if pos == 0 {
+				total += bonusFirstChar
+			}
+			continue
+		}
+
+		gap := pos - positions[i-1] - 1
+		if gap == 0 {
+			total += bonusConsecutive
+		} else {
+			total -= penaltyGapStart + (gap-1)*penaltyGapExtend
+		}
+	}
+
+	// 파일 이름(마지막 경로 요소)에서 일치하면 가산점
+	lastSlash := -1
+	for i, r := range text {
+		if r == '/' {
+			lastSlash = i
+		}
+	}
+	if len(positions) > 0 && positions[0] > lastSlash {
+		total += bonusBoundary
+	}
+
+	return total
+}
+
+func boundaryBonus(text []rune, pos int) int {
+	if pos == 0 {
+		return bonusBoundary
+	}
+
+	prev, curr := text[pos-1], text[pos]
+	switch {
+	case prev == '/':
+		return bonusPathBoundary
+	case prev == '_' || prev == '-' || prev == '.' || prev == ' ':
+		return bonusBoundary
+	case unicode.IsLower(prev) && unicode.IsUpper(curr):
+		return bonusCamelCase
+	}
+
+	return 0
+}
+
+// 취소를 확인하는 간격 (항목 수)
+const rankCheckInterval = 1024
+
+// 점수가 높은 순, 같으면 짧은 경로 순으로 정렬. ctx 가 취소되면 중단
+func Rank(ctx context.Context, pattern string, entries []Entry) ([]Result, error) {
+	results := make([]Result, 0)
+
+	for i, entry := range entries {
+		if i%rankCheckInterval == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		s, positions, ok := Match(pattern, entry.Rel)
+		if !ok {
+			continue
+		}
+		results = append(results, Result{Entry: entry, Score: s, Positions: positions})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		return ranksBefore(results[i], results[j])
+	})
+
+	return results, nil
+}
+
+func ranksBefore(a, b Result) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	if len(a.Rel) != len(b.Rel) {
+		return len(a.Rel) < len(b.Rel)
+	}
+	return a.Rel < b.Rel
+}
+
+// Rank 로 정렬된 두 결과를 순서를 지키며 합침
+func Merge(a, b []Result) []Result {
+	merged := make([]Result, 0, len(a)+len(b))
+	for len(a) > 0 && len(b) > 0 {
+		if ranksBefore(b[0], a[0]) {
+			merged = append(merged, b[0])
+			b = b[1:]
+		} else {
+			merged = append(merged, a[0])
+			a = a[1:]
+		}
+	}
+	merged = append(merged, a...)
+	return append(merged, b...)
+}
@@ -0,0 +1,233 @@
+package search
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// 백그라운드 인덱서가 채우는 경로 목록. 인덱싱 중에도 조회 가능
+type Index struct {
+	mu      sync.RWMutex
+	entries []Entry
+	dirs    int
+	done    bool
+
+	cancel context.CancelFunc
+}
+
+type IndexOptions struct {
+	Root           string
+	Workers        int
+	FollowSymlinks bool
+	// true 를 반환하면 해당 경로를 건너뜀 (디렉토리면 하위 전체)
+	Ignore func(path string, isDir bool) bool
+}
+
+func (ix *Index) Snapshot() []Entry {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	// entries 는 뒤에 추가만 되므로 길이를 잘라 공유해도 안전
+	return ix.entries[:len(ix.entries):len(ix.entries)]
+}
+
+func (ix *Index) Progress() (files, dirs int, done bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	return len(ix.entries), ix.dirs, ix.done
+}
+
+func (ix *Index) Cancel() {
+	ix.cancel()
+}
+
+func (ix *Index) add(batch []Entry) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.entries = append(ix.entries, batch...)
+	ix.dirs++
+}
+
+func (ix *Index) finish() {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	ix.done = true
+}
+
+// 제한된 수의 워커로 루트를 재귀 탐색하는 인덱서를 시작
+func StartIndex(ctx context.Context, opts IndexOptions) *Index {
+	ctx, cancel := context.WithCancel(ctx)
+
+	if opts.Workers <= 0 {
+		opts.Workers = min(runtime.NumCPU(), 8)
+	}
+
+	ix := &Index{cancel: cancel}
+
+	w := &indexWalker{
+		ctx:     ctx,
+		opts:    opts,
+		index:   ix,
+		visited: make(map[string]bool),
+	}
+	w.cond = sync.NewCond(&w.mu)
+	// 하위의 링크가 루트를 가리켜도 루트를 다시 탐색하지 않도록 먼저 방문 표시
+	w.markVisited(opts.Root)
+	w.push(opts.Root)
+
+	var wg sync.WaitGroup
+	for range opts.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.work()
+		}()
+	}
+
+	// 취소되면 대기 중인 워커를 깨움
+	go func() {
+		<-ctx.Done()
+		w.mu.Lock()
+		w.cond.Broadcast()
+		w.mu.Unlock()
+	}()
+
+	go func() {
+		wg.Wait()
+		cancel()
+		ix.finish()
+	}()
+
+	return ix
+}
+
+type indexWalker struct {
+	ctx   context.Context
+	opts  IndexOptions
+	index *Index
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	queue   []string
+	pending int
+	visited map[string]bool
+}
+
+func (w *indexWalker) push(dir string) {
+	w.mu.Lock()
+	w.queue = append(w.queue, dir)
+	w.pending++
+	w.mu.Unlock()
+
+	w.cond.Signal()
+}
+
+func (w *indexWalker) next() (string, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for len(w.queue) == 0 && w.pending > 0 && w.ctx.Err() == nil {
+		w.cond.Wait()
+	}
+	if len(w.queue) == 0 || w.ctx.Err() != nil {
+		return "", false
+	}
+
+	dir := w.queue[len(w.queue)-1]
+	w.queue = w.queue[:len(w.queue)-1]
+	return dir, true
+}
+
+func (w *indexWalker) done() {
+	w.mu.Lock()
+	w.pending--
+	if w.pending == 0 {
+		w.cond.Broadcast()
+	}
+	w.mu.Unlock()
+}
+
+func (w *indexWalker) work() {
+	for {
+		dir, ok := w.next()
+		if !ok {
+			return
+		}
+
+		w.scan(dir)
+		w.done()
+	}
+}
+
+func (w *indexWalker) scan(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	batch := make([]Entry, 0, len(entries))
+	for _, de := range entries {
+		if w.ctx.Err() != nil {
+			return
+		}
+
+		path := filepath.Join(dir, de.Name())
+		isDir := de.IsDir()
+
+		if de.Type()&os.ModeSymlink != 0 && w.opts.FollowSymlinks {
+			if info, err := os.Stat(path); err == nil && info.IsDir() {
+				isDir = w.markVisited(path)
+			}
+		}
+
+		if w.opts.Ignore != nil && w.opts.Ignore(path, isDir) {
+			continue
+		}
+
+		rel, err := filepath.Rel(w.opts.Root, path)
+		if err != nil {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+
+		batch = append(batch, Entry{Rel: rel, Path: path, IsDir: isDir, Hidden: isHiddenPath(rel)})
+		if isDir {
+			w.push(path)
+		}
+	}
+
+	w.index.add(batch)
+}
+
+// 심볼릭 링크 순환을 막기 위해 실제 경로 기준으로 한 번만 방문
+func (w *indexWalker) markVisited(path string) bool {
+	real, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return false
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.visited[real] {
+		return false
+	}
+	w.visited[real] = true
+	return true
+}
+
+func isHiddenPath(rel string) bool {
+	for _, part := range strings.Split(rel, "/") {
+		if strings.HasPrefix(part, ".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package search
+
+import (
+	"context"
+	"sync"
+)
+
+// 한 검색어에 대한 순위 계산 결과. Index 의 앞 Count 개 항목까지 반영함
+type Ranking struct {
+	Query   string
+	Results []Result
+	Index   *Index
+	Count   int
+}
+
+// 검색어가 바뀔 때마다 이전 계산을 취소하고 UI 고루틴 밖에서 순위를 매김
+type Ranker struct {
+	results chan Ranking
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+}
+
+func NewRanker() *Ranker {
+	return &Ranker{results: make(chan Ranking, 1)}
+}
+
+func (r *Ranker) Results() <-chan Ranking {
+	return r.results
+}
+
+func (r *Ranker) Request(ix *Index, query string, showHidden bool) {
+	r.Extend(Ranking{Query: query, Index: ix}, showHidden)
+}
+
+// 이미 계산한 결과에 그 뒤로 인덱스에 추가된 항목만 순위를 매겨 합침
+func (r *Ranker) Extend(previous Ranking, showHidden bool) {
+	r.mu.Lock()
+	if r.cancel != nil {
+		r.cancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.mu.Unlock()
+
+	entries := previous.Index.Snapshot()
+	go func() {
+		added, err := Rank(ctx, previous.Query, VisibleEntries(entries[previous.Count:], showHidden))
+		if err != nil {
+			return
+		}
+
+		ranking := previous
+		ranking.Results = Merge(previous.Results, added)
+		ranking.Count = len(entries)
+		select {
+		case r.results <- ranking:
+		case <-ctx.Done():
+		}
+	}()
+}
+
+func (r *Ranker) Cancel() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cancel != nil {
+		r.cancel()
+		r.cancel = nil
+	}
+}
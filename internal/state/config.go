@@ -1,24 +1,96 @@
 package state
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/minimal1/twf-clone/internal/theme"
+)
+
+// 화면 높이 설정. Value 가 0 이면 전체 화면
+type Height struct {
+	Value   int
+	Percent bool
+}
+
+func ParseHeight(s string) (Height, error) {
+	s = strings.TrimSpace(s)
+	percent := strings.HasSuffix(s, "%")
+
+	value, err := strconv.Atoi(strings.TrimSuffix(s, "%"))
+	if err != nil || value < 0 || (percent && value > 100) {
+		return Height{}, fmt.Errorf("invalid height %q (want lines like 20 or a percentage like 40%%)", s)
+	}
+
+	return Height{Value: value, Percent: percent}, nil
+}
+
+func (h Height) String() string {
+	if h.Percent {
+		return fmt.Sprintf("%d%%", h.Value)
+	}
+	return strconv.Itoa(h.Value)
+}
+
+// 터미널 높이에 맞춘 실제 줄 수. 0 이면 전체 화면 사용
+func (h Height) Rows(termHeight int) int {
+	rows := h.Value
+	if h.Percent {
+		rows = termHeight * h.Value / 100
+	}
+
+	if rows <= 0 || rows >= termHeight {
+		return 0
+	}
+	return max(rows, 3)
+}
+
 type ConfigState struct {
 	defaultPath string
 	maxHistory  int
 
 	colorScheme     string
+	theme           *theme.Theme
 	showLineNumbers bool
 
 	confirmDelete  bool
 	followSymlinks bool
+
+	showHidden     bool
+	showIgnored    bool
+	ignoreGlobs    []string
+	sortBy         SortType
+	sortReverse    bool
+	dirsFirst      bool
+	ignoreCase     bool
+	previewCommand string
+	height         Height
+	openers        []OpenRule
 }
 
 func NewConfigState() *ConfigState {
 	return &ConfigState{
 		defaultPath:     ".",
 		maxHistory:      50,
-		colorScheme:     "dark",
+		colorScheme:     theme.DefaultName,
+		theme:           theme.Default(),
 		showLineNumbers: false,
 		confirmDelete:   true,
 		followSymlinks:  false,
+		showHidden:      false,
+		showIgnored:     false,
+		ignoreGlobs:     []string{},
+		sortBy:          SortByName,
+		sortReverse:     false,
+		dirsFirst:       true,
+		ignoreCase:      true,
+		previewCommand:  "",
+		height:          Height{},
+		openers: []OpenRule{
+			{Pattern: "text/*", Command: "${EDITOR:-vi} {}"},
+			{Pattern: "*", Command: "xdg-open {}", Detach: true},
+		},
 	}
 }
 
@@ -43,6 +115,14 @@ func (cs *ConfigState) SetColorScheme(value string) {
 	cs.colorScheme = value
 }
 
+// colorScheme 이름이나 파일로 읽어 들인 테마
+func (cs *ConfigState) GetTheme() *theme.Theme {
+	return cs.theme
+}
+func (cs *ConfigState) SetTheme(value *theme.Theme) {
+	cs.theme = value
+}
+
 func (cs *ConfigState) GetShowLineNumbers() bool {
 	return cs.showLineNumbers
 }
@@ -63,3 +143,84 @@ func (cs *ConfigState) GetFollowSymlinks() bool {
 func (cs *ConfigState) SetFollowSymlinks(value bool) {
 	cs.followSymlinks = value
 }
+
+func (cs *ConfigState) GetShowHidden() bool {
+	return cs.showHidden
+}
+func (cs *ConfigState) SetShowHidden(value bool) {
+	cs.showHidden = value
+}
+
+func (cs *ConfigState) GetShowIgnored() bool {
+	return cs.showIgnored
+}
+func (cs *ConfigState) SetShowIgnored(value bool) {
+	cs.showIgnored = value
+}
+
+// .gitignore 외에 추가로 무시할 glob
+func (cs *ConfigState) GetIgnoreGlobs() []string {
+	return cs.ignoreGlobs
+}
+func (cs *ConfigState) SetIgnoreGlobs(value []string) {
+	cs.ignoreGlobs = value
+}
+
+func (cs *ConfigState) GetSortType() SortType {
+	return cs.sortBy
+}
+func (cs *ConfigState) SetSortType(value SortType) {
+	cs.sortBy = value
+}
+
+func (cs *ConfigState) GetSortReverse() bool {
+	return cs.sortReverse
+}
+func (cs *ConfigState) SetSortReverse(value bool) {
+	cs.sortReverse = value
+}
+
+func (cs *ConfigState) GetDirsFirst() bool {
+	return cs.dirsFirst
+}
+func (cs *ConfigState) SetDirsFirst(value bool) {
+	cs.dirsFirst = value
+}
+
+func (cs *ConfigState) GetIgnoreCase() bool {
+	return cs.ignoreCase
+}
+func (cs *ConfigState) SetIgnoreCase(value bool) {
+	cs.ignoreCase = value
+}
+
+func (cs *ConfigState) GetPreviewCommand() string {
+	return cs.previewCommand
+}
+func (cs *ConfigState) SetPreviewCommand(value string) {
+	cs.previewCommand = value
+}
+
+func (cs *ConfigState) GetHeight() Height {
+	return cs.height
+}
+func (cs *ConfigState) SetHeight(value Height) {
+	cs.height = value
+}
+
+// 위에서부터 처음 맞는 규칙을 사용
+func (cs *ConfigState) GetOpeners() []OpenRule {
+	return cs.openers
+}
+func (cs *ConfigState) SetOpeners(value []OpenRule) {
+	cs.openers = value
+}
+
+func (cs *ConfigState) FindOpener(filePath, mimeType string) (OpenRule, bool) {
+	for _, rule := range cs.openers {
+		if rule.Match(filePath, mimeType) {
+			return rule, true
+		}
+	}
+	return OpenRule{}, false
+}
@@ -1,6 +1,7 @@
 package state
 
 import (
+	"slices"
 	"time"
 
 	"github.com/minimal1/twf-clone/internal/filetree"
@@ -43,6 +44,10 @@ func (cs *CursorState) SetCurrentNode(node *filetree.TreeNode) {
 	cs.currentNode = node
 }
 
+func (cs *CursorState) SetMaxHistory(value int) {
+	cs.maxHistory = value
+}
+
 func (cs *CursorState) GetPosition() Position {
 	return cs.position
 }
@@ -93,3 +98,16 @@ func (cs *CursorState) addToHistory(node *filetree.TreeNode, pos Position) {
 		cs.history = cs.history[1:]
 	}
 }
+
+// 커서 노드가 트리에서 빠졌으면 남아 있는 가장 가까운 조상으로 옮기고 기록도 정리
+func (cs *CursorState) Prune(contains func(*filetree.TreeNode) bool) {
+	node := cs.currentNode
+	for node != nil && !contains(node) {
+		node = node.Parent
+	}
+	cs.currentNode = node
+
+	cs.history = slices.DeleteFunc(cs.history, func(nav Navigation) bool {
+		return !contains(nav.Node)
+	})
+}
@@ -0,0 +1,122 @@
+package state
+
+import (
+	"os"
+	"time"
+)
+
+type OperationKind int
+
+const (
+	OperationCopy OperationKind = iota
+	OperationMove
+	OperationDelete
+	OperationChmod
+	OperationRename
+)
+
+var operationKindNames = []string{"copy", "move", "delete", "chmod", "rename"}
+
+func (k OperationKind) String() string {
+	return operationKindNames[k]
+}
+
+// 파일 하나에 대한 변경. 삭제면 To 는 휴지통 위치, chmod 면 From 만 사용
+type FileChange struct {
+	From    string
+	To      string
+	OldMode os.FileMode
+	NewMode os.FileMode
+
+	// 복사나 이동이 덮어쓴 항목을 옮겨 둔 휴지통 경로
+	Replaced string
+
+	// 복사한 직후의 크기와 수정 시각. 되돌리기 전에 복사본이 바뀌지 않았는지 확인
+	Size    int64
+	ModTime time.Time
+}
+
+type Operation struct {
+	Kind      OperationKind
+	Changes   []FileChange
+	Timestamp time.Time
+}
+
+// 되돌리기/다시 실행용 파일 작업 기록. CursorState.history 처럼 maxHistory 로 제한
+type OperationHistory struct {
+	undo       []Operation
+	redo       []Operation
+	maxHistory int
+}
+
+func NewOperationHistory() *OperationHistory {
+	return &OperationHistory{
+		undo:       make([]Operation, 0),
+		redo:       make([]Operation, 0),
+		maxHistory: 50,
+	}
+}
+
+func (oh *OperationHistory) SetMaxHistory(value int) {
+	oh.maxHistory = value
+}
+
+// 새 작업을 기록. 다시 실행할 작업은 버림
+func (oh *OperationHistory) Record(op Operation) {
+	oh.PushUndo(op)
+	oh.redo = oh.redo[:0]
+}
+
+func (oh *OperationHistory) CanUndo() bool {
+	return len(oh.undo) > 0
+}
+
+func (oh *OperationHistory) CanRedo() bool {
+	return len(oh.redo) > 0
+}
+
+func (oh *OperationHistory) PeekUndo() (Operation, bool) {
+	if !oh.CanUndo() {
+		return Operation{}, false
+	}
+	return oh.undo[len(oh.undo)-1], true
+}
+
+func (oh *OperationHistory) PeekRedo() (Operation, bool) {
+	if !oh.CanRedo() {
+		return Operation{}, false
+	}
+	return oh.redo[len(oh.redo)-1], true
+}
+
+func (oh *OperationHistory) PopUndo() (Operation, bool) {
+	op, ok := oh.PeekUndo()
+	if ok {
+		oh.undo = oh.undo[:len(oh.undo)-1]
+	}
+	return op, ok
+}
+
+func (oh *OperationHistory) PopRedo() (Operation, bool) {
+	op, ok := oh.PeekRedo()
+	if ok {
+		oh.redo = oh.redo[:len(oh.redo)-1]
+	}
+	return op, ok
+}
+
+func (oh *OperationHistory) PushUndo(op Operation) {
+	oh.undo = appendCapped(oh.undo, op, oh.maxHistory)
+}
+
+func (oh *OperationHistory) PushRedo(op Operation) {
+	oh.redo = appendCapped(oh.redo, op, oh.maxHistory)
+}
+
+func appendCapped(ops []Operation, op Operation, limit int) []Operation {
+	ops = append(ops, op)
+	if len(ops) > limit {
+		ops = ops[len(ops)-limit:]
+	}
+	return ops
+}
@@ -0,0 +1,63 @@
+package state
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// 파일을 열 때 쓸 명령 규칙. "패턴=명령" 형식이며 명령이 & 로 끝나면 TUI 와 분리해 실행.
+// 패턴은 "."으로 시작하면 확장자, "/"가 있으면 MIME 타입, 그 외에는 파일 이름 glob
+type OpenRule struct {
+	Pattern string
+	Command string
+	Detach  bool
+}
+
+func ParseOpenRule(s string) (OpenRule, error) {
+	pattern, command, ok := strings.Cut(s, "=")
+	pattern, command = strings.TrimSpace(pattern), strings.TrimSpace(command)
+	if !ok || pattern == "" || command == "" {
+		return OpenRule{}, fmt.Errorf("invalid opener %q (want pattern=command)", s)
+	}
+
+	rule := OpenRule{Pattern: pattern}
+	if trimmed, detach := strings.CutSuffix(command, "&"); detach {
+		rule.Command, rule.Detach = strings.TrimSpace(trimmed), true
+	} else {
+		rule.Command = command
+	}
+	if rule.Command == "" {
+		return OpenRule{}, fmt.Errorf("invalid opener %q (empty command)", s)
+	}
+
+	// 잘못된 glob 은 미리 알림
+	if !strings.HasPrefix(pattern, ".") {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return OpenRule{}, fmt.Errorf("invalid opener pattern %q: %w", pattern, err)
+		}
+	}
+
+	return rule, nil
+}
+
+func (r OpenRule) String() string {
+	if r.Detach {
+		return r.Pattern + "=" + r.Command + " &"
+	}
+	return r.Pattern + "=" + r.Command
+}
+
+func (r OpenRule) Match(filePath, mimeType string) bool {
+	switch {
+	case strings.HasPrefix(r.Pattern, "."):
+		return strings.EqualFold(filepath.Ext(filePath), r.Pattern)
+	case strings.Contains(r.Pattern, "/"):
+		ok, _ := path.Match(r.Pattern, mimeType)
+		return ok
+	default:
+		ok, _ := path.Match(r.Pattern, filepath.Base(filePath))
+		return ok
+	}
+}
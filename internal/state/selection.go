@@ -1,6 +1,7 @@
 package state
 
 import (
+	"maps"
 	"slices"
 
 	"github.com/minimal1/twf-clone/internal/filetree"
@@ -79,6 +80,21 @@ func (ss *SelectionState) GetClipboard() []*filetree.TreeNode {
 	return ss.clipboard
 }
 
+func (ss *SelectionState) GetClipboardType() ClipboardType {
+	return ss.clipboardType
+}
+
 func (ss *SelectionState) ClearClipboard() {
 	ss.clipboard = make([]*filetree.TreeNode, 0)
 }
+
+// 트리에서 빠진 노드를 선택, 마크, 클립보드에서 제거
+func (ss *SelectionState) Prune(contains func(*filetree.TreeNode) bool) {
+	detached := func(node *filetree.TreeNode) bool { return !contains(node) }
+
+	ss.selectedNodes = slices.DeleteFunc(ss.selectedNodes, detached)
+	ss.clipboard = slices.DeleteFunc(ss.clipboard, detached)
+	maps.DeleteFunc(ss.marks, func(_ string, node *filetree.TreeNode) bool {
+		return detached(node)
+	})
+}
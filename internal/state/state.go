@@ -7,14 +7,20 @@ type AppState struct {
 	selection *SelectionState
 	view      *ViewState
 	config    *ConfigState
+	history   *OperationHistory
 }
 
 func NewAppState() *AppState {
+	return NewAppStateWithConfig(NewConfigState())
+}
+
+func NewAppStateWithConfig(config *ConfigState) *AppState {
 	return &AppState{
 		cursor:    NewCursorState(),
 		selection: NewSelectionState(),
 		view:      NewViewState(),
-		config:    NewConfigState(),
+		config:    config,
+		history:   NewOperationHistory(),
 	}
 }
 
@@ -34,10 +40,30 @@ func (as *AppState) Config() *ConfigState {
 	return as.config
 }
 
+func (as *AppState) History() *OperationHistory {
+	return as.history
+}
+
+// 새로고침이나 파일 시스템 변경으로 트리에서 빠진 노드에 대한 참조를 정리
+func (as *AppState) Prune(contains func(*filetree.TreeNode) bool) {
+	as.cursor.Prune(contains)
+	as.selection.Prune(contains)
+}
+
 func (as *AppState) Initialize(rootNode *filetree.TreeNode) error {
 	if rootNode != nil {
 		as.cursor.SetCurrentNode(rootNode)
 	}
 
+	// 설정값으로 뷰/커서 상태 초기화
+	as.view.SetShowHidden(as.config.GetShowHidden())
+	as.view.SetShowIgnored(as.config.GetShowIgnored())
+	as.view.SetSortType(as.config.GetSortType())
+	as.view.SetSortReverse(as.config.GetSortReverse())
+	as.view.SetDirsFirst(as.config.GetDirsFirst())
+	as.view.SetIgnoreCase(as.config.GetIgnoreCase())
+	as.cursor.SetMaxHistory(as.config.GetMaxHistory())
+	as.history.SetMaxHistory(as.config.GetMaxHistory())
+
 	return nil
 }
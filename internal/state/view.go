@@ -1,11 +1,21 @@
 package state
 
+import (
+	"fmt"
+	"strings"
+
+	"github.com/minimal1/twf-clone/internal/filetree"
+)
+
+// 순서는 filetree.SortKey 와 같음
 type SortType int
 
 const (
 	SortByName SortType = iota
 	SortBySize
 	SortByDate
+	SortByType
+	SortByNatural
 )
 
 type ViewMode int
@@ -14,9 +24,31 @@ const (
 	ViewModeNormal ViewMode = iota
 	ViewModeSearch
 	ViewModeHelp
+	ViewModeJobs
+	ViewModeTrash
+	ViewModeOutput
 )
 
-const sortTypeCount = 3
+const sortTypeCount = 5
+
+var sortTypeNames = []string{"name", "size", "date", "type", "natural"}
+
+func (st SortType) String() string {
+	if int(st) < len(sortTypeNames) {
+		return sortTypeNames[st]
+	}
+	return "unknown"
+}
+
+func ParseSortType(s string) (SortType, error) {
+	for i, name := range sortTypeNames {
+		if strings.EqualFold(s, name) {
+			return SortType(i), nil
+		}
+	}
+
+	return SortByName, fmt.Errorf("invalid sort %q (want one of %s)", s, strings.Join(sortTypeNames, ", "))
+}
 
 type InputMode int
 
@@ -24,27 +56,53 @@ const (
 	InputModeNormal InputMode = iota
 	InputModeWaitingForMark
 	InputModeWaitingForJump
+	InputModeFilter
+	InputModeSearch
+	InputModeConfirmDelete
+	InputModeResolveConflict
+	InputModeChmod
+	InputModeCommand
 )
 
 type ViewState struct {
 	scrollOffset int
 	sortBy       SortType
+	sortReverse  bool
+	dirsFirst    bool
+	ignoreCase   bool
 	mode         ViewMode
 	filterText   string
 	showHidden   bool
+	showIgnored  bool
 	promptMsg    string
 	inputMode    InputMode
+	inputText    string
+	message      string
+
+	searchQuery string
+
+	indexedCount int
+	indexing     bool
+
+	outputTitle  string
+	output       []string
+	outputScroll int
 }
 
 func NewViewState() *ViewState {
 	return &ViewState{
 		scrollOffset: 0,
 		sortBy:       SortByName,
+		sortReverse:  false,
+		dirsFirst:    true,
+		ignoreCase:   true,
 		mode:         ViewModeNormal,
 		filterText:   "",
 		showHidden:   false,
+		showIgnored:  false,
 		promptMsg:    "",
 		inputMode:    InputModeNormal,
+		inputText:    "",
 	}
 }
 
@@ -70,9 +128,21 @@ func (vs *ViewState) ScrollDown(amount int) {
 func (vs *ViewState) ToggleHidden() {
 	vs.showHidden = !vs.showHidden
 }
+func (vs *ViewState) SetShowHidden(value bool) {
+	vs.showHidden = value
+}
 func (vs *ViewState) ShowHidden() bool {
 	return vs.showHidden
 }
+func (vs *ViewState) ToggleIgnored() {
+	vs.showIgnored = !vs.showIgnored
+}
+func (vs *ViewState) SetShowIgnored(value bool) {
+	vs.showIgnored = value
+}
+func (vs *ViewState) ShowIgnored() bool {
+	return vs.showIgnored
+}
 
 // 정렬
 func (vs *ViewState) GetSortType() SortType {
@@ -84,6 +154,38 @@ func (vs *ViewState) SetSortType(sortType SortType) {
 func (vs *ViewState) CycleSortType() {
 	vs.sortBy = (vs.sortBy + 1) % sortTypeCount
 }
+func (vs *ViewState) SetSortReverse(value bool) {
+	vs.sortReverse = value
+}
+func (vs *ViewState) ToggleSortReverse() {
+	vs.sortReverse = !vs.sortReverse
+}
+func (vs *ViewState) SortReverse() bool {
+	return vs.sortReverse
+}
+func (vs *ViewState) SetDirsFirst(value bool) {
+	vs.dirsFirst = value
+}
+func (vs *ViewState) SetIgnoreCase(value bool) {
+	vs.ignoreCase = value
+}
+func (vs *ViewState) SortDescription() string {
+	desc := vs.sortBy.String()
+	if vs.sortReverse {
+		desc += " (reverse)"
+	}
+	return desc
+}
+
+// filetree 에 적용할 정렬 옵션
+func (vs *ViewState) SortOptions() filetree.SortOptions {
+	return filetree.SortOptions{
+		Key:        filetree.SortKey(vs.sortBy),
+		Reverse:    vs.sortReverse,
+		DirsFirst:  vs.dirsFirst,
+		IgnoreCase: vs.ignoreCase,
+	}
+}
 
 // 필터, 검색
 func (vs *ViewState) SetFilter(text string) {
@@ -96,6 +198,16 @@ func (vs *ViewState) ClearFilter() {
 	vs.filterText = ""
 }
 
+// 보이는 노드를 계산할 때 쓰는 옵션
+func (vs *ViewState) VisibleOptions() filetree.ViewOptions {
+	return filetree.ViewOptions{
+		ShowHidden:  vs.showHidden,
+		ShowIgnored: vs.showIgnored,
+		Filter:      vs.filterText,
+		Sort:        vs.SortOptions(),
+	}
+}
+
 // 뷰 모드
 func (vs *ViewState) GetMode() ViewMode {
 	return vs.mode
@@ -115,6 +227,23 @@ func (vs *ViewState) IsWaitingForInput() bool {
 	return vs.inputMode != InputModeNormal
 }
 
+// 프롬프트에 입력 중인 텍스트
+func (vs *ViewState) GetInputText() string {
+	return vs.inputText
+}
+func (vs *ViewState) SetInputText(text string) {
+	vs.inputText = text
+}
+func (vs *ViewState) AppendInput(text string) {
+	vs.inputText += text
+}
+func (vs *ViewState) DeleteInputChar() {
+	runes := []rune(vs.inputText)
+	if len(runes) > 0 {
+		vs.inputText = string(runes[:len(runes)-1])
+	}
+}
+
 // prompt 메세지 관리
 func (vs *ViewState) SetPrompt(msg string) {
 	vs.promptMsg = msg
@@ -125,3 +254,58 @@ func (vs *ViewState) GetPrompt() string {
 func (vs *ViewState) ClearPrompt() {
 	vs.promptMsg = ""
 }
+
+// 다음 키 입력 전까지 상태바에 보여줄 알림 메세지
+func (vs *ViewState) SetMessage(msg string) {
+	vs.message = msg
+}
+func (vs *ViewState) GetMessage() string {
+	return vs.message
+}
+func (vs *ViewState) ClearMessage() {
+	vs.message = ""
+}
+
+// 검색
+func (vs *ViewState) EnterSearchMode() {
+	vs.mode = ViewModeSearch
+	vs.searchQuery = ""
+}
+func (vs *ViewState) ExitSearchMode() {
+	vs.mode = ViewModeNormal
+}
+func (vs *ViewState) ClearSearch() {
+	vs.searchQuery = ""
+}
+func (vs *ViewState) UpdateSearchQuery(query string) {
+	vs.searchQuery = query
+}
+func (vs *ViewState) GetSearchQuery() string {
+	return vs.searchQuery
+}
+
+// 백그라운드 인덱싱 진행 상황
+func (vs *ViewState) SetIndexProgress(count int, indexing bool) {
+	vs.indexedCount = count
+	vs.indexing = indexing
+}
+func (vs *ViewState) GetIndexProgress() (count int, indexing bool) {
+	return vs.indexedCount, vs.indexing
+}
+
+// 마지막으로 실행한 비동기 명령의 출력. output 뷰에서 사용
+func (vs *ViewState) SetOutput(title string, lines []string) {
+	vs.outputTitle = title
+	vs.output = lines
+	vs.outputScroll = 0
+}
+func (vs *ViewState) GetOutput() (title string, lines []string) {
+	return vs.outputTitle, vs.output
+}
+
+func (vs *ViewState) GetOutputScroll() int {
+	return vs.outputScroll
+}
+func (vs *ViewState) ScrollOutput(delta int) {
+	vs.outputScroll = min(max(vs.outputScroll+delta, 0), max(len(vs.output)-1, 0))
+}
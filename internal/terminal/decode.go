@@ -0,0 +1,309 @@
+package terminal
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// 이보다 긴 CSI 시퀀스는 끝을 기다리지 않고 버림
+const maxSequenceLength = 32
+
+// 브래킷 붙여넣기의 시작과 끝 표시
+var (
+	pasteStart = []byte("\x1b[200~")
+	pasteEnd   = []byte("\x1b[201~")
+)
+
+// 입력 바이트를 키 이벤트로 나눔. 끝이 시퀀스 중간에서 잘렸으면 그 부분을 rest 로 돌려주고,
+// flush 가 true 면 (더 올 입력이 없으면) 잘린 부분도 있는 그대로 해석
+func decodeInput(data []byte, flush bool) (events []Event, rest []byte) {
+	for len(data) > 0 {
+		event, size := decodeKey(data, flush)
+		if size == 0 {
+			return events, data
+		}
+		if event != nil {
+			events = append(events, event)
+		}
+		data = data[size:]
+	}
+	return events, nil
+}
+
+// data 맨 앞의 키 하나를 해석. 더 읽어야 하면 size 가 0, 버릴 바이트면 event 가 nil
+func decodeKey(data []byte, flush bool) (Event, int) {
+	if data[0] != 0x1b {
+		return decodePlain(data, flush)
+	}
+
+	if len(data) == 1 {
+		if flush {
+			return KeyPressEvent{Key: KeyEsc}, 1
+		}
+		return nil, 0
+	}
+
+	if bytes.HasPrefix(data, pasteStart) {
+		return decodePaste(data)
+	}
+
+	switch data[1] {
+	case '[':
+		event, size := decodeCSI(data)
+		if size > 0 || !flush {
+			return event, size
+		}
+	case 'O':
+		event, size := decodeSS3(data)
+		if size > 0 || !flush {
+			return event, size
+		}
+	case 0x1b:
+		// Esc 를 연달아 누른 것으로 봄
+		return KeyPressEvent{Key: KeyEsc}, 1
+	}
+
+	// Esc 다음의 키는 Alt 와 함께 누른 것
+	event, size := decodePlain(data[1:], flush)
+	if size == 0 {
+		return nil, 0
+	}
+	if key, ok := event.(KeyPressEvent); ok {
+		key.Mod |= ModAlt
+		event = key
+	}
+	return event, size + 1
+}
+
+// 끝 표시까지를 한 번에 PasteEvent 로. 안의 내용은 키로 해석하지 않음
+func decodePaste(data []byte) (Event, int) {
+	end := bytes.Index(data, pasteEnd)
+	if end < 0 {
+		return nil, 0
+	}
+
+	text := string(data[len(pasteStart):end])
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	return PasteEvent{Text: text}, end + len(pasteEnd)
+}
+
+func inPaste(data []byte) bool {
+	return bytes.HasPrefix(data, pasteStart)
+}
+
+// 이스케이프 시퀀스가 아닌 한 바이트 제어 문자나 UTF-8 문자
+func decodePlain(data []byte, flush bool) (Event, int) {
+	b := data[0]
+	switch {
+	case b == '\r' || b == '\n':
+		return KeyPressEvent{Key: KeyEnter}, 1
+	case b == '\t':
+		return KeyPressEvent{Key: KeyTab}, 1
+	case b == 0x08 || b == 0x7f:
+		return KeyPressEvent{Key: KeyBackspace}, 1
+	case b == 0x1b:
+		return KeyPressEvent{Key: KeyEsc}, 1
+	case b == 0:
+		return KeyPressEvent{Rune: ' ', Mod: ModCtrl}, 1
+	case b >= 1 && b <= 26:
+		return KeyPressEvent{Key: KeyCtrlA + Key(b-1)}, 1
+	case b < 0x20:
+		// Ctrl+\ ] ^ _
+		return KeyPressEvent{Rune: rune(b) + 0x40, Mod: ModCtrl}, 1
+	}
+
+	if !utf8.FullRune(data) && !flush {
+		return nil, 0
+	}
+	r, size := utf8.DecodeRune(data)
+	if r == utf8.RuneError && size <= 1 {
+		// 잘못된 UTF-8 바이트는 건너뜀
+		return nil, 1
+	}
+	return KeyPressEvent{Rune: r}, size
+}
+
+// ESC [ <매개변수> <중간 바이트> <최종 바이트>
+func decodeCSI(data []byte) (Event, int) {
+	end := 2
+	for end < len(data) && data[end] >= 0x20 && data[end] <= 0x3f {
+		end++
+	}
+	if end == len(data) {
+		if len(data) >= maxSequenceLength {
+			return KeyPressEvent{Key: KeyUnknown}, len(data)
+		}
+		return nil, 0
+	}
+	if data[end] < 0x40 || data[end] > 0x7e {
+		// 잘못된 시퀀스는 여기까지 버림
+		return KeyPressEvent{Key: KeyUnknown}, end
+	}
+
+	final := data[end]
+	size := end + 1
+	if data[2] == '<' && (final == 'M' || final == 'm') {
+		return decodeSGRMouse(strings.Split(string(data[3:end]), ";"), final == 'm'), size
+	}
+
+	// 포커스 보고 (?1004h)
+	if end == 2 && (final == 'I' || final == 'O') {
+		return FocusEvent{Focused: final == 'I'}, size
+	}
+
+	// DECRQM 응답: ESC [ ? <모드> ; <값> $ y
+	if final == 'y' && data[2] == '?' && data[end-1] == '$' {
+		params := strings.Split(string(data[3:end-1]), ";")
+		return ModeReportEvent{Mode: param(params, 0, 0), Value: param(params, 1, 0)}, size
+	}
+
+	params := strings.Split(string(data[2:end]), ";")
+
+	key := KeyPressEvent{Key: KeyUnknown}
+	if final == '~' {
+		key.Key = tildeKey(param(params, 0, 0))
+	} else if k, ok := finalKey(final); ok {
+		key.Key = k
+	}
+	if final == 'Z' {
+		// Shift+Tab
+		key.Mod |= ModShift
+	}
+
+	key.Mod |= modifier(param(params, 1, 1))
+	return key, size
+}
+
+// ESC [ < 버튼 ; x ; y M (누름) 또는 m (뗌)
+func decodeSGRMouse(params []string, release bool) Event {
+	code := param(params, 0, 0)
+	event := MouseEvent{
+		X: param(params, 1, 1),
+		Y: param(params, 2, 1),
+	}
+
+	if code&4 != 0 {
+		event.Mod |= ModShift
+	}
+	if code&8 != 0 {
+		event.Mod |= ModAlt
+	}
+	if code&16 != 0 {
+		event.Mod |= ModCtrl
+	}
+
+	switch {
+	case code&64 != 0:
+		event.Button = MouseWheelUp + MouseButton(code&3)
+	case code&3 == 3:
+		event.Button = MouseNone
+	default:
+		event.Button = MouseLeft + MouseButton(code&3)
+	}
+
+	switch {
+	case release:
+		event.Action = MouseRelease
+	case code&32 != 0:
+		event.Action = MouseDrag
+	}
+	return event
+}
+
+// ESC O <최종 바이트>. 일부 터미널은 수식 키를 ESC O 5A 처럼 보냄
+func decodeSS3(data []byte) (Event, int) {
+	end := 2
+	for end < len(data) && data[end] >= '0' && data[end] <= '9' {
+		end++
+	}
+	if end == len(data) {
+		return nil, 0
+	}
+
+	key := KeyPressEvent{Key: KeyUnknown}
+	if k, ok := finalKey(data[end]); ok {
+		key.Key = k
+	}
+	if end > 2 {
+		mod, _ := strconv.Atoi(string(data[2:end]))
+		key.Mod = modifier(mod)
+	}
+	return key, end + 1
+}
+
+func finalKey(final byte) (Key, bool) {
+	switch final {
+	case 'A':
+		return KeyArrowUp, true
+	case 'B':
+		return KeyArrowDown, true
+	case 'C':
+		return KeyArrowRight, true
+	case 'D':
+		return KeyArrowLeft, true
+	case 'H':
+		return KeyHome, true
+	case 'F':
+		return KeyEnd, true
+	case 'P':
+		return KeyF1, true
+	case 'Q':
+		return KeyF2, true
+	case 'R':
+		return KeyF3, true
+	case 'S':
+		return KeyF4, true
+	case 'Z':
+		return KeyTab, true
+	case 'M':
+		return KeyEnter, true // 키패드 Enter (SS3)
+	}
+	return KeyUnknown, false
+}
+
+// ESC [ <번호> ~ 형식의 키
+func tildeKey(code int) Key {
+	switch code {
+	case 1, 7:
+		return KeyHome
+	case 2:
+		return KeyInsert
+	case 3:
+		return KeyDelete
+	case 4, 8:
+		return KeyEnd
+	case 5:
+		return KeyPageUp
+	case 6:
+		return KeyPageDown
+	case 11, 12, 13, 14, 15:
+		return KeyF1 + Key(code-11)
+	case 17, 18, 19, 20, 21:
+		return KeyF6 + Key(code-17)
+	case 23, 24:
+		return KeyF11 + Key(code-23)
+	}
+	return KeyUnknown
+}
+
+// xterm 은 수식 키를 1 + (Shift=1 | Alt=2 | Ctrl=4) 로 보냄
+func modifier(value int) Modifier {
+	if value <= 1 {
+		return 0
+	}
+	return Modifier(value-1) & (ModShift | ModAlt | ModCtrl)
+}
+
+func param(params []string, index, fallback int) int {
+	if index >= len(params) || params[index] == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(params[index])
+	if err != nil {
+		return fallback
+	}
+	return n
+}
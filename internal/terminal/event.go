@@ -1,14 +1,24 @@
 package terminal
 
 import (
-	"fmt"
-	"unicode/utf8"
+	"errors"
+	"os"
+	"time"
 )
 
 type EventType int
 
 const (
 	KeyPress EventType = iota
+	Mouse
+	Paste
+	Resize
+	Focus
+	ModeReport
+	Suspend
+	Resume
+	Interrupt
+	Error
 )
 
 type Event interface {
@@ -18,6 +28,7 @@ type Event interface {
 type KeyPressEvent struct {
 	Key  Key
 	Rune rune
+	Mod  Modifier
 }
 
 type Key int
@@ -35,63 +46,139 @@ const (
 
 	KeyTab
 	KeyBackspace
+
+	KeyHome
+	KeyEnd
+	KeyPageUp
+	KeyPageDown
+	KeyInsert
+	KeyDelete
+
+	KeyF1
+	KeyF2
+	KeyF3
+	KeyF4
+	KeyF5
+	KeyF6
+	KeyF7
+	KeyF8
+	KeyF9
+	KeyF10
+	KeyF11
+	KeyF12
+
+	// Ctrl+H, I, J, M 은 Backspace, Tab, Enter 와 같은 바이트라서 그쪽으로 들어옴
+	KeyCtrlA
+	KeyCtrlB
 	KeyCtrlC
 	KeyCtrlD
+	KeyCtrlE
+	KeyCtrlF
+	KeyCtrlG
+	KeyCtrlH
+	KeyCtrlI
+	KeyCtrlJ
+	KeyCtrlK
+	KeyCtrlL
+	KeyCtrlM
+	KeyCtrlN
+	KeyCtrlO
+	KeyCtrlP
+	KeyCtrlQ
+	KeyCtrlR
+	KeyCtrlS
+	KeyCtrlT
+	KeyCtrlU
+	KeyCtrlV
+	KeyCtrlW
+	KeyCtrlX
+	KeyCtrlY
+	KeyCtrlZ
+)
+
+// 함께 눌린 수식 키. xterm 의 CSI 1;<1+mod> 인코딩과 같은 비트
+type Modifier int
+
+const (
+	ModShift Modifier = 1 << iota
+	ModAlt
+	ModCtrl
 )
 
 func (e KeyPressEvent) EventType() EventType { return KeyPress }
 
-func parseInputData(data []byte) (Event, error) {
-	if len(data) == 0 {
-		return nil, fmt.Errorf("empty input")
-	}
+type MouseButton int
 
-	if len(data) == 1 {
-		switch data[0] {
-		case 13, 10: // Enter
-			return KeyPressEvent{Key: KeyEnter}, nil
-		case 27: // ESC
-			return KeyPressEvent{Key: KeyEsc}, nil
-		case 9: // TAB
-			return KeyPressEvent{Key: KeyTab}, nil
-		case 8, 127: // Backspace
-			return KeyPressEvent{Key: KeyBackspace}, nil
-		case 3: // Ctrl+C
-			return KeyPressEvent{Key: KeyCtrlC}, nil
-		case 4: // Ctrl+D
-			return KeyPressEvent{Key: KeyCtrlD}, nil
-		}
-	}
+const (
+	MouseNone MouseButton = iota
+	MouseLeft
+	MouseMiddle
+	MouseRight
+	MouseWheelUp
+	MouseWheelDown
+	MouseWheelLeft
+	MouseWheelRight
+)
 
-	if len(data) >= 3 && data[0] == 27 && data[1] == 91 {
-		switch data[2] {
-		case 65: // UP
-			return KeyPressEvent{Key: KeyArrowUp}, nil
-		case 66: // DOWN
-			return KeyPressEvent{Key: KeyArrowDown}, nil
-		case 67: // RIGHT
-			return KeyPressEvent{Key: KeyArrowRight}, nil
-		case 68: // LEFT
-			return KeyPressEvent{Key: KeyArrowLeft}, nil
-		default:
-			return KeyPressEvent{Key: KeyUnknown}, nil
-		}
-	}
+type MouseAction int
 
-	r, size := utf8.DecodeRune(data)
-	if r == utf8.RuneError && size == 1 {
-		return nil, fmt.Errorf("Invalid UTF-8")
-	}
+const (
+	MousePress MouseAction = iota
+	MouseRelease
+	MouseDrag
+)
 
-	return KeyPressEvent{Rune: r}, nil
+// 좌표는 화면 왼쪽 위가 (1, 1)
+type MouseEvent struct {
+	Button MouseButton
+	Action MouseAction
+	Mod    Modifier
+	X      int
+	Y      int
 }
 
+func (e MouseEvent) EventType() EventType { return Mouse }
+
+// 브래킷 붙여넣기로 들어온 텍스트. 줄바꿈은 \n 으로 통일
+type PasteEvent struct {
+	Text string
+}
+
+func (e PasteEvent) EventType() EventType { return Paste }
+
+// 단독 Esc 와 이스케이프 시퀀스의 시작을 구분하기 위해 다음 바이트를 기다리는 시간
+const escTimeout = 25 * time.Millisecond
+
+// 한 번 읽은 데이터에 여러 키가 들어 있을 수 있으므로 남은 이벤트는 다음 호출에서 반환
 func (t *Terminal) ReadEvent() (Event, error) {
-	buffer := make([]byte, 64)
-	n, err := t.in.Read(buffer)
-	if err != nil {
-		return nil, err
+	buffer := make([]byte, 256)
+
+	for len(t.events) == 0 {
+		// 시퀀스가 중간에 끊겼으면 잠깐만 기다렸다가 있는 그대로 해석.
+		// 붙여넣기는 끝 표시가 올 때까지 기다림
+		waiting := len(t.pending) > 0 && !inPaste(t.pending)
+		if waiting {
+			t.setDeadline(time.Now().Add(escTimeout))
+		}
+
+		n, err := t.in.Read(buffer)
+		if waiting {
+			if expired := t.setDeadline(time.Time{}); expired {
+				return nil, os.ErrDeadlineExceeded
+			}
+		}
+
+		switch {
+		case waiting && errors.Is(err, os.ErrDeadlineExceeded):
+			t.events, t.pending = decodeInput(t.pending, true)
+		case err != nil:
+			return nil, err
+		default:
+			t.events, t.pending = decodeInput(append(t.pending, buffer[:n]...), false)
+		}
 	}
 
-	return parseInputData(buffer[:n])
+	event := t.events[0]
+	t.events = t.events[1:]
+	return event, nil
 }
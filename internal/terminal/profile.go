@@ -0,0 +1,130 @@
+package terminal
+
+import "strings"
+
+// 터미널이 표시할 수 있는 색의 범위
+type ColorProfile int
+
+const (
+	ProfileNoColor ColorProfile = iota
+	ProfileANSI
+	Profile256
+	ProfileTrueColor
+)
+
+func (p ColorProfile) String() string {
+	switch p {
+	case ProfileNoColor:
+		return "none"
+	case Profile256:
+		return "256"
+	case ProfileTrueColor:
+		return "truecolor"
+	}
+	return "16"
+}
+
+// NO_COLOR 가 있으면 색을 쓰지 않고 (굵게, 밑줄 같은 속성은 유지),
+// COLORTERM 과 TERM 으로 지원하는 색 수를 짐작
+func DetectColorProfile(getenv func(string) string) ColorProfile {
+	if getenv("NO_COLOR") != "" {
+		return ProfileNoColor
+	}
+
+	term := getenv("TERM")
+	if term == "dumb" {
+		return ProfileNoColor
+	}
+
+	switch strings.ToLower(getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return ProfileTrueColor
+	}
+
+	switch {
+	case strings.HasSuffix(term, "-direct") || strings.Contains(term, "truecolor"):
+		return ProfileTrueColor
+	case strings.Contains(term, "256color"):
+		return Profile256
+	}
+	return ProfileANSI
+}
+
+func (t *Terminal) ColorProfile() ColorProfile {
+	return t.profile
+}
+
+func (t *Terminal) SetColorProfile(profile ColorProfile) {
+	t.profile = profile
+}
+
+// xterm 기본 16색
+var ansiPalette = [16][3]int{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// 256색 팔레트의 6x6x6 색 큐브 단계
+var cubeLevels = [6]int{0, 95, 135, 175, 215, 255}
+
+func paletteRGB(n int) (r, g, b int) {
+	switch {
+	case n < 16:
+		c := ansiPalette[n]
+		return c[0], c[1], c[2]
+	case n < 232:
+		n -= 16
+		return cubeLevels[n/36], cubeLevels[n/6%6], cubeLevels[n%6]
+	}
+	gray := 8 + (n-232)*10
+	return gray, gray, gray
+}
+
+// 색 큐브와 회색 단계 중 더 가까운 쪽. 16색 부분은 터미널마다 달라서 쓰지 않음
+func nearestIndexed(r, g, b int) int {
+	cube := 16 + 36*nearestLevel(r) + 6*nearestLevel(g) + nearestLevel(b)
+
+	average := (r + g + b) / 3
+	gray := 232 + min(max((average-3)/10, 0), 23)
+
+	cr, cg, cb := paletteRGB(cube)
+	gr, gg, gb := paletteRGB(gray)
+	if distance(r, g, b, gr, gg, gb) < distance(r, g, b, cr, cg, cb) {
+		return gray
+	}
+	return cube
+}
+
+func nearestLevel(v int) int {
+	best := 0
+	for i, level := range cubeLevels {
+		if abs(v-level) < abs(v-cubeLevels[best]) {
+			best = i
+		}
+	}
+	return best
+}
+
+func nearestANSI(r, g, b int) int {
+	best, bestDistance := 0, -1
+	for i, c := range ansiPalette {
+		if d := distance(r, g, b, c[0], c[1], c[2]); bestDistance < 0 || d < bestDistance {
+			best, bestDistance = i, d
+		}
+	}
+	return best
+}
+
+func distance(r1, g1, b1, r2, g2, b2 int) int {
+	dr, dg, db := r1-r2, g1-g2, b1-b2
+	return dr*dr + dg*dg + db*db
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
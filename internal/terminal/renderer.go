@@ -16,6 +16,18 @@ const (
 	// 스크린 모드
 	AltScreenOn  = "\x1b[?1049h"
 	AltScreenOff = "\x1b[?1049l"
+
+	// 마우스: 클릭(1000), 버튼을 누른 채 이동(1002), SGR 좌표 형식(1006)
+	MouseOn  = "\x1b[?1000h\x1b[?1002h\x1b[?1006h"
+	MouseOff = "\x1b[?1006l\x1b[?1002l\x1b[?1000l"
+
+	// 붙여넣은 텍스트를 ESC [200~ ... ESC [201~ 로 감싸서 보냄
+	BracketedPasteOn  = "\x1b[?2004h"
+	BracketedPasteOff = "\x1b[?2004l"
+
+	// 창이 포커스를 얻으면 ESC [I, 잃으면 ESC [O
+	FocusReportingOn  = "\x1b[?1004h"
+	FocusReportingOff = "\x1b[?1004l"
 )
 
 // 화면 제어
@@ -42,6 +54,33 @@ func (t *Terminal) ExitAltScreen() error {
 	return err
 }
 
+func (t *Terminal) EnableMouse() error {
+	_, err := t.out.Write([]byte(MouseOn))
+	return err
+}
+func (t *Terminal) DisableMouse() error {
+	_, err := t.out.Write([]byte(MouseOff))
+	return err
+}
+
+func (t *Terminal) EnableBracketedPaste() error {
+	_, err := t.out.Write([]byte(BracketedPasteOn))
+	return err
+}
+func (t *Terminal) DisableBracketedPaste() error {
+	_, err := t.out.Write([]byte(BracketedPasteOff))
+	return err
+}
+
+func (t *Terminal) EnableFocusReporting() error {
+	_, err := t.out.Write([]byte(FocusReportingOn))
+	return err
+}
+func (t *Terminal) DisableFocusReporting() error {
+	_, err := t.out.Write([]byte(FocusReportingOff))
+	return err
+}
+
 // Cursor 제어
 func (t *Terminal) MoveCursorHome() error {
 	_, err := t.out.Write([]byte(CursorHome))
@@ -62,31 +101,9 @@ func (t *Terminal) ShowCursor() error {
 	return err
 }
 
-type Color string
-
-const (
-	ColorReset  Color = "\x1b[0m"
-	ColorBlack  Color = "\x1b[30m"
-	ColorRed    Color = "\x1b[31m"
-	ColorGreen  Color = "\x1b[32m"
-	ColorYellow Color = "\x1b[33m"
-	ColorBlue   Color = "\x1b[34m"
-	ColorPurple Color = "\x1b[35m"
-	ColorCyan   Color = "\x1b[36m"
-	ColorWhite  Color = "\x1b[37m"
-)
-
-type Style string
-
-const (
-	StyleBold      Style = "\x1b[1m"
-	StyleDim       Style = "\x1b[2m"
-	StyleUnderline Style = "\x1b[4m"
-)
-
 func (t *Terminal) WriteColored(text string, color Color) error {
-	coloredText := string(color) + text + string(ColorReset)
-	_, err := t.out.Write([]byte(coloredText))
+	style := Style{Fg: color}
+	_, err := t.out.Write([]byte(style.Sequence(t.profile) + text + SGRReset))
 	return err
 }
 
@@ -0,0 +1,198 @@
+package terminal
+
+import (
+	"bytes"
+	"strconv"
+)
+
+// 동기화 출력: 켜 둔 동안 터미널이 화면 갱신을 모았다가 한 번에 보여 줌
+const (
+	SyncOutputBegin = "\x1b[?2026h"
+	SyncOutputEnd   = "\x1b[?2026l"
+
+	// DECRQM 으로 동기화 출력 지원 여부를 물음. 응답은 ModeReportEvent 로 들어옴
+	SyncOutputQuery = "\x1b[?2026$p"
+	SyncOutputMode  = 2026
+)
+
+// 화면 한 칸. 전각 문자는 두 칸을 차지하고 오른쪽 칸은 Width 가 0 인 빈 칸
+type Cell struct {
+	Rune  rune
+	Width int
+	Style Style
+}
+
+var blankCell = Cell{Rune: ' ', Width: 1}
+
+// 뷰가 그리는 화면 버퍼. Flush 할 때 이전 프레임과 비교해 바뀐 칸만 한 번에 씀
+type Screen struct {
+	term   *Terminal
+	width  int
+	height int
+	top    int
+
+	cells []Cell
+	prev  []Cell
+
+	// prev 를 믿을 수 없어서 (처음, 크기 변경, 외부 프로그램 실행 뒤) 전부 다시 그려야 함
+	invalid    bool
+	syncOutput bool
+
+	buffer bytes.Buffer
+}
+
+func NewScreen(term *Terminal) *Screen {
+	return &Screen{term: term, top: 1, invalid: true}
+}
+
+func (s *Screen) Resize(width, height int) {
+	if width == s.width && height == s.height {
+		return
+	}
+
+	s.width, s.height = max(width, 0), max(height, 0)
+	s.cells = make([]Cell, s.width*s.height)
+	s.prev = make([]Cell, s.width*s.height)
+	s.Clear()
+	s.invalid = true
+}
+
+// 그리기 시작 행. 전체 화면이면 1
+func (s *Screen) SetOrigin(top int) {
+	if top != s.top {
+		s.top = top
+		s.invalid = true
+	}
+}
+
+// 다음 Flush 에서 이전 프레임과 비교하지 않고 전부 다시 그림
+func (s *Screen) Invalidate() {
+	s.invalid = true
+}
+
+func (s *Screen) SetSyncOutput(enabled bool) {
+	s.syncOutput = enabled
+}
+
+// 새 프레임을 그리기 전에 버퍼를 빈 칸으로 채움
+func (s *Screen) Clear() {
+	for i := range s.cells {
+		s.cells[i] = blankCell
+	}
+}
+
+// 화면 좌표 (row, col) 의 칸. row 는 터미널 기준 (top 부터)
+func (s *Screen) SetCell(row, col int, cell Cell) {
+	x, y := col-1, row-s.top
+	if x < 0 || y < 0 || x >= s.width || y >= s.height {
+		return
+	}
+	// 오른쪽 칸이 화면 밖이면 전각 문자를 그릴 수 없음
+	if cell.Width == 2 && x+1 >= s.width {
+		cell = Cell{Rune: ' ', Width: 1, Style: cell.Style}
+	}
+
+	line := s.cells[y*s.width : (y+1)*s.width]
+
+	// 전각 문자의 반쪽만 덮으면 나머지 반쪽은 빈 칸으로
+	if line[x].Width == 0 && x > 0 {
+		line[x-1] = blankCell
+	}
+	if line[x].Width == 2 && cell.Width != 2 && x+1 < s.width {
+		line[x+1] = blankCell
+	}
+
+	line[x] = cell
+	if cell.Width == 2 {
+		if x+2 < s.width && line[x+1].Width == 2 {
+			line[x+2] = blankCell
+		}
+		line[x+1] = Cell{Style: cell.Style}
+	}
+}
+
+// Terminal.WriteColoredAt 처럼 쓰되 버퍼에 그림. 그린 폭을 반환
+func (s *Screen) WriteStyledAt(row, col int, text string, style Style) int {
+	x := col
+	for _, r := range text {
+		width := RuneWidth(r)
+		// 결합 문자처럼 폭이 없는 문자는 칸에 담을 수 없어서 버림
+		if width == 0 {
+			continue
+		}
+		s.SetCell(row, x, Cell{Rune: r, Width: width, Style: style})
+		x += width
+	}
+	return x - col
+}
+
+// 바뀐 칸만 골라 한 번의 Write 로 내보냄
+func (s *Screen) Flush() error {
+	b := &s.buffer
+	b.Reset()
+
+	if s.syncOutput {
+		b.WriteString(SyncOutputBegin)
+	}
+
+	// 커서 위치와 현재 속성을 기억해 불필요한 이동과 SGR 을 줄임
+	cursorX, cursorY := -1, -1
+	profile := s.term.ColorProfile()
+	var current Cell
+	styled := false
+
+	for y := 0; y < s.height; y++ {
+		for x := 0; x < s.width; x++ {
+			i := y*s.width + x
+			cell := s.cells[i]
+			if cell.Width == 0 {
+				// 전각 문자의 오른쪽 칸은 왼쪽 칸과 함께 그려짐
+				continue
+			}
+			if !s.invalid && cell == s.prev[i] && (cell.Width != 2 || s.cells[i+1] == s.prev[i+1]) {
+				continue
+			}
+
+			if x != cursorX || y != cursorY {
+				b.WriteString("\x1b[")
+				b.WriteString(strconv.Itoa(s.top + y))
+				b.WriteByte(';')
+				b.WriteString(strconv.Itoa(x + 1))
+				b.WriteByte('H')
+			}
+			if !styled || !sameStyle(cell, current) {
+				b.WriteString(cell.Style.Sequence(profile))
+				current, styled = cell, true
+			}
+
+			b.WriteRune(cell.Rune)
+			cursorX, cursorY = x+cell.Width, y
+		}
+	}
+
+	copy(s.prev, s.cells)
+	s.invalid = false
+
+	// 바뀐 칸이 없음
+	if !styled {
+		return nil
+	}
+
+	b.WriteString(SGRReset)
+	if s.syncOutput {
+		b.WriteString(SyncOutputEnd)
+	}
+	_, err := s.term.Write(b.Bytes())
+	return err
+}
+
+// cell 을 current 의 속성 그대로 그려도 되는지. 밑줄이나 반전이 없는 공백에는 글자색이 보이지 않음
+func sameStyle(cell, current Cell) bool {
+	if cell.Style.Bg != current.Style.Bg || cell.Style.Attrs != current.Style.Attrs {
+		return false
+	}
+	if cell.Rune == ' ' && cell.Style.Attrs&(AttrUnderline|AttrReverse) == 0 {
+		return true
+	}
+	return cell.Style.Fg == current.Style.Fg
+}
@@ -0,0 +1,172 @@
+package terminal
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// 터미널 크기가 바뀜 (SIGWINCH)
+type ResizeEvent struct {
+	Width  int
+	Height int
+}
+
+// 터미널 창이 포커스를 얻거나 잃음 (?1004h 를 켰을 때)
+type FocusEvent struct {
+	Focused bool
+}
+
+// DECRQM 질의에 대한 응답. Value 가 1 (켜짐) 이나 2 (꺼짐) 면 지원하는 모드
+type ModeReportEvent struct {
+	Mode  int
+	Value int
+}
+
+// Ctrl-Z 로 멈춰 달라는 요청
+type SuspendEvent struct{}
+
+// 멈췄다가 SIGCONT 로 다시 실행됨
+type ResumeEvent struct{}
+
+// SIGINT, SIGTERM, SIGHUP 으로 종료 요청을 받음
+type InterruptEvent struct {
+	Signal os.Signal
+}
+
+// 터미널을 더 읽을 수 없음. 이 이벤트 뒤로는 키 입력이 오지 않음
+type ErrorEvent struct {
+	Err error
+}
+
+func (e ResizeEvent) EventType() EventType     { return Resize }
+func (e FocusEvent) EventType() EventType      { return Focus }
+func (e ModeReportEvent) EventType() EventType { return ModeReport }
+func (e SuspendEvent) EventType() EventType    { return Suspend }
+func (e ResumeEvent) EventType() EventType     { return Resume }
+func (e InterruptEvent) EventType() EventType  { return Interrupt }
+func (e ErrorEvent) EventType() EventType      { return Error }
+
+// 키 입력과 시그널을 하나로 모은 이벤트 채널. 처음 호출할 때 읽기 고루틴을 시작
+func (t *Terminal) Events() <-chan Event {
+	t.startOnce.Do(func() {
+		t.eventCh = make(chan Event)
+		t.done = make(chan struct{})
+		t.paused = make(chan struct{})
+		t.resume = make(chan struct{})
+		t.readerDone = make(chan struct{})
+
+		t.signals = make(chan os.Signal, 1)
+		// SIGTSTP 는 받지 않음. Go 가 한 번 처리기를 설치하면 기본 동작(멈춤)으로 되돌릴 수 없어서
+		// Ctrl-Z 로 실행한 외부 프로그램과 함께 멈추지 못함
+		signal.Notify(t.signals, syscall.SIGWINCH, syscall.SIGCONT,
+			syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+
+		go t.readLoop()
+		go t.signalLoop()
+	})
+	return t.eventCh
+}
+
+func (t *Terminal) stopEvents() {
+	if t.done == nil {
+		return
+	}
+	t.stopOnce.Do(func() {
+		signal.Stop(t.signals)
+		close(t.done)
+	})
+}
+
+func (t *Terminal) readLoop() {
+	defer close(t.readerDone)
+
+	for {
+		event, err := t.ReadEvent()
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			// PauseEvents 로 외부 프로그램이 터미널을 쓰는 동안 멈춤
+			select {
+			case t.paused <- struct{}{}:
+			case <-t.done:
+				return
+			}
+			select {
+			case <-t.resume:
+			case <-t.done:
+				return
+			}
+			continue
+		}
+		if err != nil {
+			t.send(ErrorEvent{Err: err})
+			return
+		}
+
+		// raw 모드에서는 Ctrl-Z 가 SIGTSTP 대신 키로 들어옴
+		if key, ok := event.(KeyPressEvent); ok && key.Key == KeyCtrlZ {
+			event = SuspendEvent{}
+		}
+		t.send(event)
+	}
+}
+
+func (t *Terminal) signalLoop() {
+	for {
+		select {
+		case sig := <-t.signals:
+			switch sig {
+			case syscall.SIGWINCH:
+				width, height, err := t.GetSize()
+				if err == nil {
+					t.send(ResizeEvent{Width: width, Height: height})
+				}
+			case syscall.SIGCONT:
+				t.send(ResumeEvent{})
+			default:
+				t.send(InterruptEvent{Signal: sig})
+			}
+		case <-t.done:
+			return
+		}
+	}
+}
+
+func (t *Terminal) send(event Event) {
+	select {
+	case t.eventCh <- event:
+	case <-t.done:
+	}
+}
+
+// 대기 중인 Read 를 깨워 읽기 고루틴이 멈출 때까지 기다림. 그 사이에 온 이벤트는 버림.
+// 멈춘 뒤에는 Read 로 터미널을 직접 읽을 수 있음
+func (t *Terminal) PauseEvents() {
+	if t.done == nil {
+		return
+	}
+	t.SetReadDeadline(time.Now())
+
+	for paused := false; !paused; {
+		select {
+		case <-t.paused:
+			paused = true
+		case <-t.readerDone:
+			paused = true
+		case <-t.eventCh:
+		}
+	}
+
+	t.SetReadDeadline(time.Time{})
+}
+
+func (t *Terminal) ResumeEvents() {
+	if t.done == nil {
+		return
+	}
+	select {
+	case t.resume <- struct{}{}:
+	case <-t.readerDone:
+	}
+}
@@ -0,0 +1,273 @@
+package terminal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const SGRReset = "\x1b[0m"
+
+type colorKind uint8
+
+const (
+	colorDefault colorKind = iota
+	colorANSI              // 기본 16색 (0-7, 밝은 색 8-15)
+	colorIndexed           // 256색 팔레트
+	colorRGB               // 24비트
+)
+
+// 글자색이나 배경색. 0 값은 터미널 기본색
+type Color struct {
+	kind  colorKind
+	value uint32
+}
+
+func ANSIColor(n int) Color {
+	return Color{kind: colorANSI, value: uint32(n & 15)}
+}
+
+func IndexedColor(n int) Color {
+	return Color{kind: colorIndexed, value: uint32(n & 255)}
+}
+
+func RGBColor(r, g, b uint8) Color {
+	return Color{kind: colorRGB, value: uint32(r)<<16 | uint32(g)<<8 | uint32(b)}
+}
+
+var (
+	ColorDefault = Color{}
+	ColorBlack   = ANSIColor(0)
+	ColorRed     = ANSIColor(1)
+	ColorGreen   = ANSIColor(2)
+	ColorYellow  = ANSIColor(3)
+	ColorBlue    = ANSIColor(4)
+	ColorPurple  = ANSIColor(5)
+	ColorCyan    = ANSIColor(6)
+	ColorWhite   = ANSIColor(7)
+	ColorGray    = ANSIColor(8)
+)
+
+var colorNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+// "default", 색 이름 ("red", "bright-red", "gray"), 256색 번호 ("208"), "#rrggbb" 또는 "#rgb"
+func ParseColor(s string) (Color, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+
+	switch name {
+	case "default", "":
+		return ColorDefault, nil
+	case "gray", "grey":
+		return ColorGray, nil
+	case "purple":
+		return ColorPurple, nil
+	}
+
+	for i, colorName := range colorNames {
+		switch name {
+		case colorName:
+			return ANSIColor(i), nil
+		case "bright-" + colorName, "bright" + colorName:
+			return ANSIColor(i + 8), nil
+		}
+	}
+
+	if hex, ok := strings.CutPrefix(name, "#"); ok {
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		value, err := strconv.ParseUint(hex, 16, 32)
+		if len(hex) != 6 || err != nil {
+			return Color{}, fmt.Errorf("invalid color %q (want #rrggbb)", s)
+		}
+		return Color{kind: colorRGB, value: uint32(value)}, nil
+	}
+
+	if n, err := strconv.Atoi(name); err == nil {
+		if n < 0 || n > 255 {
+			return Color{}, fmt.Errorf("invalid color %q (want 0-255)", s)
+		}
+		return IndexedColor(n), nil
+	}
+
+	return Color{}, fmt.Errorf("unknown color %q", s)
+}
+
+// 터미널이 표시할 수 있는 가장 가까운 색으로 바꿈
+func (c Color) Convert(profile ColorProfile) Color {
+	if c.kind == colorDefault {
+		return c
+	}
+
+	switch profile {
+	case ProfileNoColor:
+		return ColorDefault
+	case Profile256:
+		if c.kind == colorRGB {
+			return IndexedColor(nearestIndexed(c.rgb()))
+		}
+	case ProfileANSI:
+		if c.kind == colorIndexed && c.value < 16 {
+			return ANSIColor(int(c.value))
+		}
+		if c.kind != colorANSI {
+			return ANSIColor(nearestANSI(c.rgb()))
+		}
+	}
+	return c
+}
+
+func (c Color) rgb() (r, g, b int) {
+	switch c.kind {
+	case colorRGB:
+		return int(c.value >> 16 & 0xff), int(c.value >> 8 & 0xff), int(c.value & 0xff)
+	case colorANSI, colorIndexed:
+		return paletteRGB(int(c.value))
+	}
+	return 0, 0, 0
+}
+
+// SGR 매개변수. 기본색이면 빈 문자열
+func (c Color) parameter(background bool) string {
+	base := 30
+	if background {
+		base = 40
+	}
+
+	switch c.kind {
+	case colorANSI:
+		if c.value >= 8 {
+			return strconv.Itoa(base + 60 + int(c.value) - 8)
+		}
+		return strconv.Itoa(base + int(c.value))
+	case colorIndexed:
+		return fmt.Sprintf("%d;5;%d", base+8, c.value)
+	case colorRGB:
+		r, g, b := c.rgb()
+		return fmt.Sprintf("%d;2;%d;%d;%d", base+8, r, g, b)
+	}
+	return ""
+}
+
+type Attr uint8
+
+const (
+	AttrBold Attr = 1 << iota
+	AttrDim
+	AttrItalic
+	AttrUnderline
+	AttrReverse
+)
+
+var attrNames = []struct {
+	name      string
+	attr      Attr
+	parameter string
+}{
+	{"bold", AttrBold, "1"},
+	{"dim", AttrDim, "2"},
+	{"italic", AttrItalic, "3"},
+	{"underline", AttrUnderline, "4"},
+	{"reverse", AttrReverse, "7"},
+}
+
+type Style struct {
+	Fg    Color
+	Bg    Color
+	Attrs Attr
+}
+
+// "bold yellow on #202020" 처럼 속성, 글자색, on 뒤에 배경색
+func ParseStyle(spec string) (Style, error) {
+	var style Style
+	background := false
+
+	for _, word := range strings.Fields(strings.ToLower(spec)) {
+		if word == "on" {
+			background = true
+			continue
+		}
+
+		found := false
+		for _, a := range attrNames {
+			if word == a.name {
+				style.Attrs |= a.attr
+				found = true
+			}
+		}
+		if found {
+			continue
+		}
+
+		color, err := ParseColor(word)
+		if err != nil {
+			return Style{}, err
+		}
+		if background {
+			style.Bg = color
+		} else {
+			style.Fg = color
+		}
+		background = false
+	}
+
+	if background {
+		return Style{}, fmt.Errorf("missing background color after \"on\" in %q", spec)
+	}
+	return style, nil
+}
+
+// 이전 속성을 지우고 style 을 적용하는 SGR 시퀀스. 색은 profile 에 맞게 바꿈
+func (s Style) Sequence(profile ColorProfile) string {
+	var b strings.Builder
+	b.WriteString("\x1b[0")
+
+	for _, a := range attrNames {
+		if s.Attrs&a.attr != 0 {
+			b.WriteString(";" + a.parameter)
+		}
+	}
+	if p := s.Fg.Convert(profile).parameter(false); p != "" {
+		b.WriteString(";" + p)
+	}
+	if p := s.Bg.Convert(profile).parameter(true); p != "" {
+		b.WriteString(";" + p)
+	}
+
+	b.WriteByte('m')
+	return b.String()
+}
+
+func (s Style) String() string {
+	var words []string
+	for _, a := range attrNames {
+		if s.Attrs&a.attr != 0 {
+			words = append(words, a.name)
+		}
+	}
+	if s.Fg.kind != colorDefault {
+		words = append(words, s.Fg.String())
+	}
+	if s.Bg.kind != colorDefault {
+		words = append(words, "on", s.Bg.String())
+	}
+	return strings.Join(words, " ")
+}
+
+func (c Color) String() string {
+	switch c.kind {
+	case colorANSI:
+		if c.value >= 8 {
+			if c.value == 8 {
+				return "gray"
+			}
+			return "bright-" + colorNames[c.value-8]
+		}
+		return colorNames[c.value]
+	case colorIndexed:
+		return strconv.Itoa(int(c.value))
+	case colorRGB:
+		return fmt.Sprintf("#%06x", c.value)
+	}
+	return "default"
+}
@@ -2,6 +2,8 @@ package terminal
 
 import (
 	"os"
+	"sync"
+	"time"
 
 	"golang.org/x/term"
 )
@@ -10,6 +12,24 @@ type Terminal struct {
 	originalState *term.State
 	in            *os.File
 	out           *os.File
+	profile       ColorProfile
+
+	// ReadEvent 가 해석하고 아직 반환하지 않은 이벤트와 끝나지 않은 시퀀스
+	events  []Event
+	pending []byte
+
+	mu       sync.Mutex
+	deadline time.Time
+
+	// Events 가 시작하는 읽기 고루틴과 시그널 처리
+	startOnce  sync.Once
+	stopOnce   sync.Once
+	eventCh    chan Event
+	signals    chan os.Signal
+	done       chan struct{}
+	paused     chan struct{}
+	resume     chan struct{}
+	readerDone chan struct{}
 }
 
 func NewTerminal() (*Terminal, error) {
@@ -20,21 +40,25 @@ func NewTerminal() (*Terminal, error) {
 	}
 
 	newTerminal := &Terminal{
-		in:  tty,
-		out: tty,
+		in:      tty,
+		out:     tty,
+		profile: DetectColorProfile(os.Getenv),
 	}
 
 	return newTerminal, nil
 }
 
+// 이미 raw 모드면 (SIGCONT 뒤 다시 켜는 경우) 처음 저장한 상태를 그대로 둠
 func (t *Terminal) EnableRawMode() error {
-	originalState, err := term.MakeRaw(int(t.in.Fd()))
+	originalState, err := term.MakeRaw(fd(t.in))
 
 	if err != nil {
 		return err
 	}
 
-	t.originalState = originalState
+	if t.originalState == nil {
+		t.originalState = originalState
+	}
 	return nil
 }
 
@@ -45,14 +69,71 @@ func (t *Terminal) DisableRawMode() error {
 
 	originalState := t.originalState
 	t.originalState = nil
-	return term.Restore(int(t.in.Fd()), originalState)
+	return term.Restore(fd(t.in), originalState)
+}
+
+func (t *Terminal) Write(p []byte) (int, error) {
+	return t.out.Write(p)
+}
+
+func (t *Terminal) Read(p []byte) (int, error) {
+	return t.in.Read(p)
+}
+
+// 과거 시각을 주면 대기 중인 Read 가 os.ErrDeadlineExceeded 로 바로 반환됨
+func (t *Terminal) SetReadDeadline(deadline time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.deadline = deadline
+	return t.in.SetReadDeadline(deadline)
+}
+
+// Esc 를 기다리는 동안 쓰는 기한. SetReadDeadline 으로 정한 기한이 더 이르면 그쪽을 따름.
+// 그 기한이 이미 지났으면 true
+func (t *Terminal) setDeadline(deadline time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	expired := !t.deadline.IsZero() && !time.Now().Before(t.deadline)
+	if deadline.IsZero() || (!t.deadline.IsZero() && t.deadline.Before(deadline)) {
+		deadline = t.deadline
+	}
+	t.in.SetReadDeadline(deadline)
+	return expired
+}
+
+// 편집기처럼 터미널을 직접 쓰는 외부 프로그램에 넘겨줄 tty.
+// 자식 프로세스에 넘기면 fd 가 blocking 으로 바뀌므로 따로 엶
+func OpenTTY() (*os.File, error) {
+	return os.OpenFile("/dev/tty", os.O_RDWR, 0)
+}
+
+// File.Fd 는 fd 를 blocking 모드로 바꿔 SetReadDeadline 이 듣지 않게 되므로 대신 사용
+func fd(f *os.File) int {
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return int(f.Fd())
+	}
+
+	var descriptor int
+	conn.Control(func(d uintptr) {
+		descriptor = int(d)
+	})
+	return descriptor
+}
+
+func IsTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
 }
 
 func (t *Terminal) GetSize() (width, height int, err error) {
-	return term.GetSize(int(t.out.Fd()))
+	return term.GetSize(fd(t.out))
 }
 
 func (t *Terminal) Cleanup() error {
+	t.stopEvents()
+
 	if err := t.DisableRawMode(); err != nil {
 		return err
 	}
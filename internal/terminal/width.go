@@ -0,0 +1,67 @@
+package terminal
+
+import "unicode"
+
+// 동아시아 전각 문자 범위 (터미널에서 두 칸을 차지)
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F},
+	{0x2E80, 0x303E},
+	{0x3041, 0x33FF},
+	{0x3400, 0x4DBF},
+	{0x4E00, 0x9FFF},
+	{0xA000, 0xA4CF},
+	{0xAC00, 0xD7A3},
+	{0xF900, 0xFAFF},
+	{0xFE30, 0xFE4F},
+	{0xFF00, 0xFF60},
+	{0xFFE0, 0xFFE6},
+	{0x1F300, 0x1F64F},
+	{0x1F900, 0x1F9FF},
+	{0x20000, 0x3FFFD},
+}
+
+func RuneWidth(r rune) int {
+	if r == 0 || r < 0x20 || (r >= 0x7f && r < 0xa0) {
+		return 0
+	}
+	if unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Me, r) || r == 0x200B {
+		return 0
+	}
+
+	for _, wide := range wideRanges {
+		if r < wide[0] {
+			break
+		}
+		if r <= wide[1] {
+			return 2
+		}
+	}
+
+	return 1
+}
+
+func StringWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		width += RuneWidth(r)
+	}
+	return width
+}
+
+// 표시 폭이 width 를 넘지 않도록 자르기
+func Truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+
+	used := 0
+	for i, r := range s {
+		w := RuneWidth(r)
+		if used+w > width {
+			return s[:i]
+		}
+		used += w
+	}
+
+	return s
+}
@@ -0,0 +1,137 @@
+package theme
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/minimal1/twf-clone/internal/terminal"
+)
+
+// 화면 요소별 스타일. 색을 쓸 수 없는 터미널(NO_COLOR)에서도 구분되도록
+// 커서와 선택은 속성도 함께 지정
+type Theme struct {
+	Text     terminal.Style // 트리, 미리보기, 명령 출력의 본문
+	Muted    terminal.Style // 무시된 파일, 빈 목록 안내, 취소된 작업
+	Accent   terminal.Style // 제목, 현재 경로, 목록에서 고른 항목
+	Cursor   terminal.Style // 트리의 커서
+	Selected terminal.Style // 선택한 노드
+	Match    terminal.Style // 검색에서 일치한 글자와 목록 표시
+	Error    terminal.Style // 오류와 상태 메시지
+	Status   terminal.Style // 상태바 오른쪽 정보
+	Border   terminal.Style // 트리와 미리보기 사이 구분선
+	Prompt   terminal.Style // 입력 프롬프트
+}
+
+// 테마 파일에서 쓰는 요소 이름
+var elements = map[string]func(*Theme) *terminal.Style{
+	"text":     func(t *Theme) *terminal.Style { return &t.Text },
+	"muted":    func(t *Theme) *terminal.Style { return &t.Muted },
+	"accent":   func(t *Theme) *terminal.Style { return &t.Accent },
+	"cursor":   func(t *Theme) *terminal.Style { return &t.Cursor },
+	"selected": func(t *Theme) *terminal.Style { return &t.Selected },
+	"match":    func(t *Theme) *terminal.Style { return &t.Match },
+	"error":    func(t *Theme) *terminal.Style { return &t.Error },
+	"status":   func(t *Theme) *terminal.Style { return &t.Status },
+	"border":   func(t *Theme) *terminal.Style { return &t.Border },
+	"prompt":   func(t *Theme) *terminal.Style { return &t.Prompt },
+}
+
+var builtins = map[string]Theme{
+	"dark": {
+		Text:     terminal.Style{Fg: terminal.ColorWhite},
+		Muted:    terminal.Style{Fg: terminal.ColorGray},
+		Accent:   terminal.Style{Fg: terminal.ColorCyan},
+		Cursor:   terminal.Style{Fg: terminal.ColorYellow, Attrs: terminal.AttrBold},
+		Selected: terminal.Style{Fg: terminal.ColorGreen, Attrs: terminal.AttrUnderline},
+		Match:    terminal.Style{Fg: terminal.ColorYellow},
+		Error:    terminal.Style{Fg: terminal.ColorRed},
+		Status:   terminal.Style{Fg: terminal.ColorYellow},
+		Border:   terminal.Style{Fg: terminal.ColorBlue},
+		Prompt:   terminal.Style{Fg: terminal.ColorBlue},
+	},
+	"light": {
+		Text:     terminal.Style{},
+		Muted:    terminal.Style{Fg: terminal.IndexedColor(245)},
+		Accent:   terminal.Style{Fg: terminal.IndexedColor(25)},
+		Cursor:   terminal.Style{Fg: terminal.IndexedColor(166), Attrs: terminal.AttrBold},
+		Selected: terminal.Style{Fg: terminal.IndexedColor(28), Attrs: terminal.AttrUnderline},
+		Match:    terminal.Style{Fg: terminal.IndexedColor(130), Attrs: terminal.AttrBold},
+		Error:    terminal.Style{Fg: terminal.IndexedColor(160)},
+		Status:   terminal.Style{Fg: terminal.IndexedColor(94)},
+		Border:   terminal.Style{Fg: terminal.IndexedColor(250)},
+		Prompt:   terminal.Style{Fg: terminal.IndexedColor(25)},
+	},
+	"solarized": {
+		Text:     terminal.Style{Fg: terminal.RGBColor(0x83, 0x94, 0x96)},
+		Muted:    terminal.Style{Fg: terminal.RGBColor(0x58, 0x6e, 0x75)},
+		Accent:   terminal.Style{Fg: terminal.RGBColor(0x2a, 0xa1, 0x98)},
+		Cursor:   terminal.Style{Fg: terminal.RGBColor(0xb5, 0x89, 0x00), Attrs: terminal.AttrBold},
+		Selected: terminal.Style{Fg: terminal.RGBColor(0x85, 0x99, 0x00), Attrs: terminal.AttrUnderline},
+		Match:    terminal.Style{Fg: terminal.RGBColor(0xcb, 0x4b, 0x16)},
+		Error:    terminal.Style{Fg: terminal.RGBColor(0xdc, 0x32, 0x2f)},
+		Status:   terminal.Style{Fg: terminal.RGBColor(0x6c, 0x71, 0xc4)},
+		Border:   terminal.Style{Fg: terminal.RGBColor(0x58, 0x6e, 0x75)},
+		Prompt:   terminal.Style{Fg: terminal.RGBColor(0x26, 0x8b, 0xd2)},
+	},
+	"high-contrast": {
+		Text:     terminal.Style{Fg: terminal.ANSIColor(15)},
+		Muted:    terminal.Style{Fg: terminal.ColorWhite},
+		Accent:   terminal.Style{Fg: terminal.ANSIColor(14), Attrs: terminal.AttrBold},
+		Cursor:   terminal.Style{Fg: terminal.ANSIColor(11), Attrs: terminal.AttrBold | terminal.AttrReverse},
+		Selected: terminal.Style{Fg: terminal.ANSIColor(10), Attrs: terminal.AttrBold | terminal.AttrUnderline},
+		Match:    terminal.Style{Fg: terminal.ANSIColor(11), Attrs: terminal.AttrBold | terminal.AttrUnderline},
+		Error:    terminal.Style{Fg: terminal.ANSIColor(9), Attrs: terminal.AttrBold},
+		Status:   terminal.Style{Fg: terminal.ANSIColor(11)},
+		Border:   terminal.Style{Fg: terminal.ANSIColor(15)},
+		Prompt:   terminal.Style{Fg: terminal.ANSIColor(14), Attrs: terminal.AttrBold},
+	},
+}
+
+const DefaultName = "dark"
+
+func Default() *Theme {
+	theme, _ := Builtin(DefaultName)
+	return theme
+}
+
+// 내장 테마의 복사본
+func Builtin(name string) (*Theme, bool) {
+	theme, ok := builtins[name]
+	if !ok {
+		return nil, false
+	}
+	return &theme, true
+}
+
+func Names() []string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// 요소 하나의 스타일을 "bold yellow on 236" 같은 형식으로 바꿈
+func (t *Theme) Set(element, spec string) error {
+	field, ok := elements[element]
+	if !ok {
+		return fmt.Errorf("unknown theme element %q (want one of %v)", element, elementNames())
+	}
+
+	style, err := terminal.ParseStyle(spec)
+	if err != nil {
+		return fmt.Errorf("%s: %w", element, err)
+	}
+	*field(t) = style
+	return nil
+}
+
+func elementNames() []string {
+	names := make([]string, 0, len(elements))
+	for name := range elements {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,344 @@
+package trash
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// freedesktop.org Trash 명세를 따르는 휴지통
+// https://specifications.freedesktop.org/trash-spec/trashspec-latest.html
+
+const (
+	infoExt    = ".trashinfo"
+	dateLayout = "2006-01-02T15:04:05"
+)
+
+// 휴지통에 들어 있는 항목 하나
+type Item struct {
+	Name         string // files/ 안의 이름
+	Path         string // files/ 안의 실제 경로
+	OriginalPath string
+	DeletedAt    time.Time
+}
+
+// $XDG_DATA_HOME/Trash
+func HomeDir() (string, error) {
+	base := os.Getenv("XDG_DATA_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		base = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(base, "Trash"), nil
+}
+
+// path 를 옮길 휴지통 안의 경로를 정하고 .trashinfo 를 기록. 실제 이동은 호출한 쪽이 함
+func Prepare(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := trashDirFor(abs)
+	if err != nil {
+		return "", err
+	}
+
+	base := filepath.Base(abs)
+	for i := 1; ; i++ {
+		name := base
+		if i > 1 {
+			name = fmt.Sprintf("%s.%d", base, i)
+		}
+
+		trashed := filepath.Join(dir, "files", name)
+		if _, err := os.Lstat(trashed); err == nil {
+			continue
+		}
+
+		err := writeInfo(dir, name, abs)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return trashed, nil
+	}
+}
+
+// 이미 정해진 휴지통 경로에 대한 .trashinfo 를 다시 기록 (다시 실행용)
+func Reserve(trashed, original string) error {
+	dir := filepath.Dir(filepath.Dir(trashed))
+	return writeInfo(dir, filepath.Base(trashed), original)
+}
+
+// 휴지통에서 꺼낸 항목의 .trashinfo 를 지움
+func Forget(trashed string) error {
+	err := os.Remove(infoPath(trashed))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// 항목을 원래 경로로 되돌릴 준비. 원래 경로에 이미 무언가 있으면 실패.
+// 옮기는 것은 호출한 쪽에서 하고, 옮긴 뒤 Forget 으로 .trashinfo 를 지움
+func PrepareRestore(item Item) error {
+	if _, err := os.Lstat(item.OriginalPath); err == nil {
+		return fmt.Errorf("%s already exists", item.OriginalPath)
+	}
+	return os.MkdirAll(filepath.Dir(item.OriginalPath), 0o755)
+}
+
+// 모든 휴지통의 항목을 지운 시각의 역순으로 나열
+func List() ([]Item, error) {
+	home, err := HomeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	dirs := []string{home}
+	for _, topdir := range mountPoints() {
+		for _, dir := range []string{sharedTrashDir(topdir), privateTrashDir(topdir)} {
+			if info, err := os.Stat(filepath.Join(dir, "info")); err == nil && info.IsDir() && dir != home {
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+
+	var items []Item
+	for _, dir := range dirs {
+		items = append(items, listDir(dir)...)
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].DeletedAt.After(items[j].DeletedAt)
+	})
+	return items, nil
+}
+
+func listDir(dir string) []Item {
+	entries, err := os.ReadDir(filepath.Join(dir, "info"))
+	if err != nil {
+		return nil
+	}
+
+	var items []Item
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), infoExt)
+		if !ok {
+			continue
+		}
+
+		item, err := readInfo(dir, name)
+		if err != nil {
+			continue
+		}
+		if _, err := os.Lstat(item.Path); err != nil {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+func readInfo(dir, name string) (Item, error) {
+	file, err := os.Open(filepath.Join(dir, "info", name+infoExt))
+	if err != nil {
+		return Item{}, err
+	}
+	defer file.Close()
+
+	item := Item{Name: name, Path: filepath.Join(dir, "files", name)}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "Path":
+			path, err := url.PathUnescape(value)
+			if err != nil {
+				return Item{}, err
+			}
+			if !filepath.IsAbs(path) {
+				// 마운트별 휴지통은 최상위 디렉토리 기준 상대 경로
+				path = filepath.Join(topdirOf(dir), path)
+			}
+			item.OriginalPath = path
+		case "DeletionDate":
+			item.DeletedAt, _ = time.ParseInLocation(dateLayout, value, time.Local)
+		}
+	}
+
+	if item.OriginalPath == "" {
+		return Item{}, fmt.Errorf("missing Path in %s", name)
+	}
+	return item, scanner.Err()
+}
+
+func writeInfo(dir, name, original string) error {
+	infoDir := filepath.Join(dir, "info")
+	if err := os.MkdirAll(infoDir, 0o700); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "files"), 0o700); err != nil {
+		return err
+	}
+
+	path := original
+	if home, err := HomeDir(); err == nil && dir != home {
+		if rel, err := filepath.Rel(topdirOf(dir), original); err == nil {
+			path = rel
+		}
+	}
+
+	// O_EXCL 로 만들어 같은 이름을 동시에 고르는 경우를 막음 (명세의 원자적 생성)
+	file, err := os.OpenFile(filepath.Join(infoDir, name+infoExt), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(file, "[Trash Info]\nPath=%s\nDeletionDate=%s\n",
+		(&url.URL{Path: path}).EscapedPath(), time.Now().Format(dateLayout))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func infoPath(trashed string) string {
+	dir := filepath.Dir(filepath.Dir(trashed))
+	return filepath.Join(dir, "info", filepath.Base(trashed)+infoExt)
+}
+
+// 홈 휴지통과 같은 파일 시스템이면 홈 휴지통, 아니면 그 마운트의 휴지통
+func trashDirFor(path string) (string, error) {
+	home, err := HomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	pathDev, err := device(path)
+	if err != nil {
+		return "", err
+	}
+	homeDev, err := device(existingAncestor(home))
+	if err != nil || homeDev == pathDev {
+		return home, nil
+	}
+
+	topdir := mountPointOf(path, pathDev)
+
+	// $topdir/.Trash 는 sticky 비트가 있고 심볼릭 링크가 아닐 때만 사용
+	shared := filepath.Join(topdir, ".Trash")
+	if info, err := os.Lstat(shared); err == nil && info.IsDir() && info.Mode()&os.ModeSticky != 0 {
+		dir := sharedTrashDir(topdir)
+		if err := os.MkdirAll(dir, 0o700); err == nil {
+			return dir, nil
+		}
+	}
+
+	dir := privateTrashDir(topdir)
+	if err := os.MkdirAll(dir, 0o700); err == nil {
+		return dir, nil
+	}
+
+	// 마운트에 휴지통을 만들 수 없으면 홈 휴지통으로 (복사 후 삭제)
+	return home, nil
+}
+
+func sharedTrashDir(topdir string) string {
+	return filepath.Join(topdir, ".Trash", strconv.Itoa(os.Getuid()))
+}
+
+func privateTrashDir(topdir string) string {
+	return filepath.Join(topdir, fmt.Sprintf(".Trash-%d", os.Getuid()))
+}
+
+// 휴지통 디렉토리가 속한 마운트의 최상위 디렉토리
+func topdirOf(dir string) string {
+	parent := filepath.Dir(dir)
+	if filepath.Base(parent) == ".Trash" {
+		return filepath.Dir(parent)
+	}
+	return parent
+}
+
+func device(path string) (uint64, error) {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return 0, err
+	}
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, fmt.Errorf("cannot stat device of %s", path)
+	}
+	return uint64(stat.Dev), nil
+}
+
+func existingAncestor(path string) string {
+	for {
+		if _, err := os.Lstat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
+
+// 같은 장치인 동안 상위 디렉토리로 올라가 마운트 지점을 찾음
+func mountPointOf(path string, dev uint64) string {
+	for {
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		if parentDev, err := device(parent); err != nil || parentDev != dev {
+			return path
+		}
+		path = parent
+	}
+}
+
+// /proc/mounts 의 마운트 지점. 없는 플랫폼에서는 빈 목록
+func mountPoints() []string {
+	file, err := os.Open("/proc/mounts")
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var points []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		// 공백 등은 \040 처럼 8진수로 이스케이프되어 있음
+		point, err := strconv.Unquote(`"` + strings.ReplaceAll(fields[1], `"`, `\"`) + `"`)
+		if err != nil {
+			point = fields[1]
+		}
+		points = append(points, point)
+	}
+	return points
+}
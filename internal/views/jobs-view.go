@@ -0,0 +1,86 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/minimal1/twf-clone/internal/jobs"
+	"github.com/minimal1/twf-clone/internal/preview"
+	"github.com/minimal1/twf-clone/internal/state"
+	"github.com/minimal1/twf-clone/internal/terminal"
+)
+
+// 파일 작업 목록. 끝난 작업의 파일별 오류도 함께 표시
+type JobsView struct {
+	snapshots []jobs.Snapshot
+	selected  int
+}
+
+func NewJobsView() *JobsView {
+	return &JobsView{}
+}
+
+type jobLine struct {
+	text  string
+	style terminal.Style
+}
+
+func (jv *JobsView) SetJobs(snapshots []jobs.Snapshot, selected int) {
+	jv.snapshots = snapshots
+	jv.selected = selected
+}
+
+func (jv *JobsView) Render(screen *terminal.Screen, rect Rect, appState *state.AppState) error {
+	snapshots := jv.snapshots
+	selected := jv.selected
+	th := appState.Config().GetTheme()
+
+	if len(snapshots) == 0 {
+		screen.WriteStyledAt(rect.Y, rect.X, terminal.Truncate(" No jobs", rect.Width), th.Muted)
+		return nil
+	}
+
+	var lines []jobLine
+	selectedLine := 0
+	for i, job := range snapshots {
+		marker, style := "  ", th.Text
+		if i == selected {
+			marker, style = "▶ ", th.Accent
+			selectedLine = len(lines)
+		}
+		switch job.Status {
+		case jobs.StatusFailed:
+			style = th.Error
+		case jobs.StatusCancelled:
+			style = th.Muted
+		}
+
+		lines = append(lines, jobLine{text: marker + formatJob(job), style: style})
+		for _, fileErr := range job.Errors {
+			lines = append(lines, jobLine{text: fmt.Sprintf("      %s: %v", fileErr.Path, fileErr.Err), style: th.Error})
+		}
+	}
+
+	offset := max(selectedLine-rect.Height+1, 0)
+	for i := offset; i < len(lines) && i < offset+rect.Height; i++ {
+		screen.WriteStyledAt(rect.Y+i-offset, rect.X, terminal.Truncate(lines[i].text, rect.Width), lines[i].style)
+	}
+
+	return nil
+}
+
+func formatJob(job jobs.Snapshot) string {
+	return fmt.Sprintf("#%d %-6s %-9s %s  %d/%d files  %s  %s/s",
+		job.ID, job.Kind, job.Status, job.Title, job.Files, job.TotalFiles,
+		preview.FormatSize(job.Bytes), preview.FormatSize(int64(job.Throughput())))
+}
+
+// 상태바용 진행 막대
+func progressBar(fraction float64, width int) string {
+	filled := int(fraction * float64(width))
+	return "[" + strings.Repeat("#", filled) + strings.Repeat("-", width-filled) + "]"
+}
+
+func (jv *JobsView) GetMinSize() (width, height int) {
+	return 20, 3
+}
@@ -6,18 +6,32 @@ import (
 )
 
 type Layout struct {
-	treeView   *TreeView
-	statusView *StatusView
-	termWidth  int
-	termHeight int
+	treeView    *TreeView
+	statusView  *StatusView
+	previewView *PreviewView
+	searchView  *SearchView
+	jobsView    *JobsView
+	trashView   *TreeView
+	outputView  *OutputView
+	showPreview bool
+	termWidth   int
+	termHeight  int
+	top         int
 }
 
-func NewLayout(treeView *TreeView, statusView *StatusView) *Layout {
+func NewLayout(treeView *TreeView, statusView *StatusView, previewView *PreviewView, searchView *SearchView, jobsView *JobsView, trashView *TreeView, outputView *OutputView) *Layout {
 	return &Layout{
-		treeView:   treeView,
-		statusView: statusView,
-		termWidth:  80,
-		termHeight: 24,
+		treeView:    treeView,
+		statusView:  statusView,
+		previewView: previewView,
+		searchView:  searchView,
+		jobsView:    jobsView,
+		trashView:   trashView,
+		outputView:  outputView,
+		showPreview: previewView != nil,
+		termWidth:   80,
+		termHeight:  24,
+		top:         1,
 	}
 }
 
@@ -26,26 +40,99 @@ func (l *Layout) SetSize(width, height int) {
 	l.termHeight = height
 }
 
-func (l *Layout) Render(term *terminal.Terminal, appState *state.AppState) error {
-	statusRect := Rect{
+// 그리기 시작 행. 전체 화면이면 1
+func (l *Layout) SetOrigin(top int) {
+	l.top = top
+}
+
+func (l *Layout) TogglePreview() {
+	l.showPreview = !l.showPreview && l.previewView != nil
+}
+
+func (l *Layout) PreviewVisible() bool {
+	if !l.showPreview || l.previewView == nil {
+		return false
+	}
+
+	minWidth, _ := l.previewView.GetMinSize()
+	return l.termWidth/2 >= minWidth
+}
+
+func (l *Layout) TreeRect() Rect {
+	width := l.termWidth
+	if l.PreviewVisible() {
+		width = l.termWidth / 2
+	}
+
+	return Rect{
 		X:      1,
-		Y:      l.termHeight,
-		Width:  l.termWidth,
-		Height: 1,
+		Y:      l.top,
+		Width:  width,
+		Height: l.termHeight - 1,
 	}
+}
 
-	treeRect := Rect{
+func (l *Layout) PreviewRect() Rect {
+	treeRect := l.TreeRect()
+
+	// 트리와 미리보기 사이에 구분선 한 칸
+	return Rect{
+		X:      treeRect.X + treeRect.Width + 2,
+		Y:      l.top,
+		Width:  l.termWidth - treeRect.Width - 2,
+		Height: l.termHeight - 1,
+	}
+}
+
+func (l *Layout) StatusRect() Rect {
+	return Rect{
 		X:      1,
-		Y:      1,
+		Y:      l.top + l.termHeight - 1,
 		Width:  l.termWidth,
-		Height: l.termHeight - 1,
+		Height: 1,
+	}
+}
+
+func (l *Layout) Render(screen *terminal.Screen, appState *state.AppState) error {
+	// 검색 중에는 트리 대신 검색 결과 목록
+	var mainView View = l.treeView
+	switch appState.View().GetMode() {
+	case state.ViewModeSearch:
+		if l.searchView != nil {
+			mainView = l.searchView
+		}
+	case state.ViewModeJobs:
+		if l.jobsView != nil {
+			mainView = l.jobsView
+		}
+	case state.ViewModeTrash:
+		if l.trashView != nil {
+			mainView = l.trashView
+		}
+	case state.ViewModeOutput:
+		if l.outputView != nil {
+			mainView = l.outputView
+		}
 	}
 
-	if err := l.treeView.Render(term, treeRect, appState); err != nil {
+	if err := mainView.Render(screen, l.TreeRect(), appState); err != nil {
 		return err
 	}
 
-	if err := l.statusView.Render(term, statusRect, appState); err != nil {
+	if l.PreviewVisible() {
+		treeRect := l.TreeRect()
+		separatorX := treeRect.X + treeRect.Width
+		th := appState.Config().GetTheme()
+		for y := treeRect.Y; y < treeRect.Y+treeRect.Height; y++ {
+			screen.WriteStyledAt(y, separatorX, "│", th.Border)
+		}
+
+		if err := l.previewView.Render(screen, l.PreviewRect(), appState); err != nil {
+			return err
+		}
+	}
+
+	if err := l.statusView.Render(screen, l.StatusRect(), appState); err != nil {
 		return err
 	}
 
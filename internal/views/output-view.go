@@ -0,0 +1,36 @@
+package views
+
+import (
+	"github.com/minimal1/twf-clone/internal/state"
+	"github.com/minimal1/twf-clone/internal/terminal"
+)
+
+// 비동기로 실행한 셸 명령의 출력. 첫 줄은 명령과 종료 상태
+type OutputView struct{}
+
+func NewOutputView() *OutputView {
+	return &OutputView{}
+}
+
+func (ov *OutputView) Render(screen *terminal.Screen, rect Rect, appState *state.AppState) error {
+	viewState := appState.View()
+	title, lines := viewState.GetOutput()
+	th := appState.Config().GetTheme()
+
+	if title == "" {
+		screen.WriteStyledAt(rect.Y, rect.X, terminal.Truncate(" No command output", rect.Width), th.Muted)
+		return nil
+	}
+	screen.WriteStyledAt(rect.Y, rect.X, terminal.Truncate(" "+title, rect.Width), th.Accent)
+
+	offset := viewState.GetOutputScroll()
+	for i := 0; i < rect.Height-1 && offset+i < len(lines); i++ {
+		screen.WriteStyledAt(rect.Y+1+i, rect.X, terminal.Truncate(lines[offset+i], rect.Width), th.Text)
+	}
+
+	return nil
+}
+
+func (ov *OutputView) GetMinSize() (width, height int) {
+	return 20, 3
+}
@@ -0,0 +1,94 @@
+package views
+
+import (
+	"github.com/minimal1/twf-clone/internal/preview"
+	"github.com/minimal1/twf-clone/internal/search"
+	"github.com/minimal1/twf-clone/internal/state"
+	"github.com/minimal1/twf-clone/internal/terminal"
+)
+
+type PreviewView struct {
+	path   string
+	lines  []string
+	err    error
+	offset int
+
+	searchResult *search.Result
+}
+
+func NewPreviewView() *PreviewView {
+	return &PreviewView{}
+}
+
+func (pv *PreviewView) SetContent(result preview.Result) {
+	// 같은 파일을 더 길게 다시 읽은 경우에는 스크롤 위치를 유지
+	if result.Path != pv.path {
+		pv.offset = 0
+	}
+	pv.path = result.Path
+	pv.lines = result.Lines
+	pv.err = result.Err
+}
+
+// 검색 중에 미리볼 선택된 검색 결과
+func (pv *PreviewView) SetSearchResult(result *search.Result) {
+	pv.searchResult = result
+}
+
+func (pv *PreviewView) Render(screen *terminal.Screen, rect Rect, appState *state.AppState) error {
+	path, name, _, ok := PreviewTarget(appState, pv.searchResult)
+	if !ok {
+		return nil
+	}
+
+	th := appState.Config().GetTheme()
+	screen.WriteStyledAt(rect.Y, rect.X, terminal.Truncate(name, rect.Width), th.Accent)
+
+	if pv.path != path {
+		screen.WriteStyledAt(rect.Y+1, rect.X, terminal.Truncate("Loading...", rect.Width), th.Text)
+		return nil
+	}
+
+	if pv.err != nil {
+		screen.WriteStyledAt(rect.Y+1, rect.X, terminal.Truncate(pv.err.Error(), rect.Width), th.Error)
+		return nil
+	}
+
+	for i := 0; i+1 < rect.Height && pv.offset+i < len(pv.lines); i++ {
+		screen.WriteStyledAt(rect.Y+1+i, rect.X, terminal.Truncate(pv.lines[pv.offset+i], rect.Width), th.Text)
+	}
+
+	return nil
+}
+
+// 휠로 미리보기를 스크롤. 읽어 둔 줄의 끝에 닿았으면 true
+func (pv *PreviewView) ScrollBy(delta, visible int) bool {
+	limit := max(len(pv.lines)-visible, 0)
+	pv.offset = min(max(pv.offset+delta, 0), limit)
+	return delta > 0 && pv.offset == limit
+}
+
+func (pv *PreviewView) LineCount() int {
+	return len(pv.lines)
+}
+
+// 미리볼 대상: 검색 중이면 선택된 검색 결과, 아니면 커서 노드
+func PreviewTarget(appState *state.AppState, searchResult *search.Result) (path, name string, isDir bool, ok bool) {
+	if appState.View().GetMode() == state.ViewModeSearch {
+		if searchResult != nil {
+			return searchResult.Path, searchResult.Rel, searchResult.IsDir, true
+		}
+		return "", "", false, false
+	}
+
+	// 휴지통 보기의 루트처럼 실제 경로가 없는 노드는 미리보지 않음
+	currentNode := appState.Cursor().GetCurrentNode()
+	if currentNode == nil || currentNode.Path == "" {
+		return "", "", false, false
+	}
+	return currentNode.Path, currentNode.Name, currentNode.IsDir, true
+}
+
+func (pv *PreviewView) GetMinSize() (width, height int) {
+	return 20, 2
+}
@@ -0,0 +1,84 @@
+package views
+
+import (
+	"slices"
+
+	"github.com/minimal1/twf-clone/internal/search"
+	"github.com/minimal1/twf-clone/internal/state"
+	"github.com/minimal1/twf-clone/internal/terminal"
+)
+
+// 퍼지 검색 결과 목록. 일치한 문자를 강조해서 표시
+type SearchView struct {
+	results  []search.Result
+	selected int
+}
+
+func NewSearchView() *SearchView {
+	return &SearchView{}
+}
+
+// 검색 결과는 앱이 들고 있다가 그리기 전에 넘김
+func (sv *SearchView) SetResults(results []search.Result, selected int) {
+	sv.results = results
+	sv.selected = selected
+}
+
+func (sv *SearchView) Render(screen *terminal.Screen, rect Rect, appState *state.AppState) error {
+	results := sv.results
+	selected := sv.selected
+	th := appState.Config().GetTheme()
+
+	offset := max(selected-rect.Height+1, 0)
+
+	for i := offset; i < len(results) && i < offset+rect.Height; i++ {
+		y := rect.Y + (i - offset)
+		result := results[i]
+
+		marker, baseStyle := "  ", th.Text
+		if i == selected {
+			marker, baseStyle = "▶ ", th.Accent
+		}
+		screen.WriteStyledAt(y, rect.X, marker, th.Match)
+
+		text := result.Rel
+		if result.IsDir {
+			text += "/"
+		}
+
+		// 같은 스타일이 이어지는 구간 단위로 출력
+		x := rect.X + terminal.StringWidth(marker)
+		limit := rect.X + rect.Width
+		var segment []rune
+		segmentStyle := baseStyle
+
+		flush := func() {
+			if len(segment) == 0 {
+				return
+			}
+			chunk := terminal.Truncate(string(segment), limit-x)
+			screen.WriteStyledAt(y, x, chunk, segmentStyle)
+			x += terminal.StringWidth(chunk)
+			segment = segment[:0]
+		}
+
+		for pos, r := range []rune(text) {
+			style := baseStyle
+			if slices.Contains(result.Positions, pos) {
+				style = th.Match
+			}
+			if style != segmentStyle {
+				flush()
+				segmentStyle = style
+			}
+			segment = append(segment, r)
+		}
+		flush()
+	}
+
+	return nil
+}
+
+func (sv *SearchView) GetMinSize() (width, height int) {
+	return 20, 3
+}
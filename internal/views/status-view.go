@@ -3,35 +3,76 @@ package views
 import (
 	"fmt"
 
+	"github.com/minimal1/twf-clone/internal/jobs"
+	"github.com/minimal1/twf-clone/internal/preview"
 	"github.com/minimal1/twf-clone/internal/state"
 	"github.com/minimal1/twf-clone/internal/terminal"
 )
 
-type StatusView struct{}
+type StatusView struct {
+	searchIndex int
+	searchCount int
 
-func (sv *StatusView) Render(term *terminal.Terminal, rect Rect, appState *state.AppState) error {
+	runningJob jobs.Snapshot
+	jobRunning bool
+}
+
+// 남아 있는 검색 결과 중 선택한 위치
+func (sv *StatusView) SetSearchPosition(index, count int) {
+	sv.searchIndex = index
+	sv.searchCount = count
+}
+
+// 실행 중인 작업 중 가장 먼저 시작한 것
+func (sv *StatusView) SetRunningJob(job jobs.Snapshot, ok bool) {
+	sv.runningJob = job
+	sv.jobRunning = ok
+}
+
+func (sv *StatusView) Render(screen *terminal.Screen, rect Rect, appState *state.AppState) error {
 	currentNode := appState.Cursor().GetCurrentNode()
 	if currentNode == nil {
 		return nil
 	}
 
+	th := appState.Config().GetTheme()
 	promptMsg := appState.View().GetPrompt()
 	if promptMsg != "" {
-		term.WriteColoredAt(rect.Y, rect.X, promptMsg, terminal.ColorBlue)
+		screen.WriteStyledAt(rect.Y, rect.X, terminal.Truncate(promptMsg, rect.Width), th.Prompt)
 		return nil
 	}
 
+	viewState := appState.View()
 	path := currentNode.Path
 	selectedCount := len(appState.Selection().GetSelectedNodes())
 
-	leftText := fmt.Sprintf(" %s", path)
-	term.WriteColoredAt(rect.Y, rect.X, leftText, terminal.ColorCyan)
-
+	rightText := fmt.Sprintf("sort: %s ", viewState.SortDescription())
 	if selectedCount > 0 {
-		rightText := fmt.Sprintf("Selected: %d", selectedCount)
-		rightX := rect.X + rect.Width - len(rightText)
-		term.WriteColoredAt(rect.Y, rightX, rightText, terminal.ColorYellow)
+		rightText = fmt.Sprintf("Selected: %d  %s", selectedCount, rightText)
 	}
+	if filter := viewState.GetFilter(); filter != "" {
+		rightText = fmt.Sprintf("filter: %s  %s", filter, rightText)
+	}
+	if count, indexing := viewState.GetIndexProgress(); indexing {
+		rightText = fmt.Sprintf("indexing %d…  %s", count, rightText)
+	}
+	if job := sv.runningJob; sv.jobRunning {
+		rightText = fmt.Sprintf("%s %s %3.0f%% %s/s  %s", job.Kind, progressBar(job.Fraction(), 10), job.Fraction()*100,
+			preview.FormatSize(int64(job.Throughput())), rightText)
+	}
+	if sv.searchCount > 0 {
+		rightText = fmt.Sprintf("/%s [%d/%d]  %s", viewState.GetSearchQuery(), sv.searchIndex+1, sv.searchCount, rightText)
+	}
+
+	leftWidth := rect.Width - terminal.StringWidth(rightText) - 1
+	if message := viewState.GetMessage(); message != "" {
+		screen.WriteStyledAt(rect.Y, rect.X, terminal.Truncate(" "+message, leftWidth), th.Error)
+	} else {
+		screen.WriteStyledAt(rect.Y, rect.X, terminal.Truncate(" "+path, leftWidth), th.Accent)
+	}
+
+	rightX := rect.X + rect.Width - terminal.StringWidth(rightText)
+	screen.WriteStyledAt(rect.Y, rightX, rightText, th.Status)
 	return nil
 }
 
@@ -18,10 +18,11 @@ func NewTreeView(walker *filetree.Walker) *TreeView {
 	}
 }
 
-func (tv *TreeView) Render(term *terminal.Terminal, rect Rect, appState *state.AppState) error {
-	visibleNodes := tv.walker.GetVisibleNodes()
+func (tv *TreeView) Render(screen *terminal.Screen, rect Rect, appState *state.AppState) error {
+	visibleNodes := tv.walker.GetVisibleNodes(appState.View().VisibleOptions())
 
 	scrollOffset := appState.View().GetScrollOffset()
+	th := appState.Config().GetTheme()
 
 	startIdx := scrollOffset
 	endIdx := scrollOffset + rect.Height
@@ -36,16 +37,19 @@ func (tv *TreeView) Render(term *terminal.Terminal, rect Rect, appState *state.A
 
 		indent := strings.Repeat("  ", node.Depth())
 
-		color := terminal.ColorWhite
+		style := th.Text
+		if node.Ignored {
+			style = th.Muted
+		}
 		if node == appState.Cursor().GetCurrentNode() {
-			color = terminal.ColorYellow
+			style = th.Cursor
 		}
 		if appState.Selection().IsSelected(node) {
-			color = terminal.ColorGreen
+			style = th.Selected
 		}
 
 		text := indent + node.GetDisplayName()
-		term.WriteColoredAt(y, rect.X, text, color)
+		screen.WriteStyledAt(y, rect.X, terminal.Truncate(text, rect.Width), style)
 	}
 
 	return nil
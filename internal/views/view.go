@@ -12,7 +12,12 @@ type Rect struct {
 	Height int
 }
 
+// 화면 좌표 (x, y) 가 영역 안에 있는지
+func (r Rect) Contains(x, y int) bool {
+	return x >= r.X && x < r.X+r.Width && y >= r.Y && y < r.Y+r.Height
+}
+
 type View interface {
-	Render(term *terminal.Terminal, rect Rect, appState *state.AppState) error
+	Render(screen *terminal.Screen, rect Rect, appState *state.AppState) error
 	GetMinSize() (width, height int)
 }